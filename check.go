@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/mrgrenier/GuitarScales/library"
+)
+
+// runCheck checks the scale and chord definitions, the built-in ones and
+// those of any -library files, and lists every problem found.
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	addLibraryFlag(fs)
+	fs.Parse(args)
+
+	l, err := library.Known()
+	if err != nil {
+		return err
+	}
+	problems := l.Check()
+	for _, err := range problems {
+		fmt.Println(err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problems in %d scales and %d chords", len(problems), len(l.Scales), len(l.Chords))
+	}
+	fmt.Printf("%d scales and %d chords, no problems found\n", len(l.Scales), len(l.Chords))
+	return nil
+}
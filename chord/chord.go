@@ -3,6 +3,9 @@ package chord
 import (
 	"container/ring"
 	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/mrgrenier/GuitarScales/note"
 	"github.com/mrgrenier/GuitarScales/scale"
 )
@@ -13,9 +16,13 @@ type Chord struct {
 	interval         *scale.Interval
 	chords2intervals map[string][]string
 	intervals2chords map[int]string
+	// aliases holds the other names of the chords defined with them.
+	aliases map[string][]string
 }
 
-func NewChord(root note.Note) *Chord {
+// NewChord returns the chords rooted on root, or an error when root is not one
+// of the twelve notes (see note.Valid).
+func NewChord(root note.Note) (*Chord, error) {
 
 	n := &Chord{}
 	n.interval = scale.NewInterval()
@@ -23,38 +30,55 @@ func NewChord(root note.Note) *Chord {
 	n.chords2intervals["Major"] = append(n.chords2intervals["Major"], "1", "3", "5")
 	n.chords2intervals["Major6th"] = append(n.chords2intervals["Major6th"], "1", "3", "5", "6")
 	n.chords2intervals["Major7th"] = append(n.chords2intervals["Major7th"], "1", "3", "5", "7")
-	n.chords2intervals["Major9th"] = append(n.chords2intervals["Major9th"], "1", "3", "5", "7", "2")
-	n.chords2intervals["Major13th"] = append(n.chords2intervals["Major13th"], "1", "3", "5", "7", "2", "6")
-	n.chords2intervals["Minor"] = append(n.chords2intervals["Minor"], "1", "3", "5")
+	n.chords2intervals["Major9th"] = append(n.chords2intervals["Major9th"], "1", "3", "5", "7", "9")
+	n.chords2intervals["Major13th"] = append(n.chords2intervals["Major13th"], "1", "3", "5", "7", "9", "13")
+	n.chords2intervals["Major7#11"] = append(n.chords2intervals["Major7#11"], "1", "3", "5", "7", "#11")
+	n.chords2intervals["Minor"] = append(n.chords2intervals["Minor"], "1", "b3", "5")
 	n.chords2intervals["Minor6th"] = append(n.chords2intervals["Minor6th"], "1", "b3", "5", "6")
 	n.chords2intervals["Minor7th"] = append(n.chords2intervals["Minor7th"], "1", "b3", "5", "b7")
-	n.chords2intervals["Minor9th"] = append(n.chords2intervals["Minor9th"], "1", "b3", "5", "b7", "2")
-	n.chords2intervals["Minor11th"] = append(n.chords2intervals["Minor11th"], "1", "b3", "5", "b7", "2", "4")
-	n.chords2intervals["Minor13th"] = append(n.chords2intervals["Minor13th"], "1", "b3", "5", "b7", "2", "6")
+	n.chords2intervals["Minor9th"] = append(n.chords2intervals["Minor9th"], "1", "b3", "5", "b7", "9")
+	n.chords2intervals["Minor11th"] = append(n.chords2intervals["Minor11th"], "1", "b3", "5", "b7", "9", "11")
+	n.chords2intervals["Minor13th"] = append(n.chords2intervals["Minor13th"], "1", "b3", "5", "b7", "9", "13")
+	n.chords2intervals["Minor7b5"] = append(n.chords2intervals["Minor7b5"], "1", "b3", "b5", "b7")
 	n.chords2intervals["Dim"] = append(n.chords2intervals["Dim"], "1", "b3", "b5")
-	n.chords2intervals["Dim7th"] = append(n.chords2intervals["Dim7th"], "1", "b3", "b5", "6")
-	n.chords2intervals["Dim7b5"] = append(n.chords2intervals["Dim7b5"], "1", "b3", "b5", "b7")
+	n.chords2intervals["Dim7th"] = append(n.chords2intervals["Dim7th"], "1", "b3", "b5", "bb7")
 	n.chords2intervals["Aug"] = append(n.chords2intervals["Aug"], "1", "3", "#5")
 	n.chords2intervals["Aug7th"] = append(n.chords2intervals["Aug7th"], "1", "3", "#5", "b7")
 	n.chords2intervals["Dom7th"] = append(n.chords2intervals["Dom7th"], "1", "3", "5", "b7")
-	n.chords2intervals["Dom9th"] = append(n.chords2intervals["Dom9th"], "1", "3", "5", "b7", "2")
-	n.chords2intervals["Dom11th"] = append(n.chords2intervals["Dom11th"], "1", "5", "b7", "2", "4")
+	n.chords2intervals["Dom9th"] = append(n.chords2intervals["Dom9th"], "1", "3", "5", "b7", "9")
+	n.chords2intervals["Dom11th"] = append(n.chords2intervals["Dom11th"], "1", "5", "b7", "9", "11")
+	n.chords2intervals["Dom7b9"] = append(n.chords2intervals["Dom7b9"], "1", "3", "5", "b7", "b9")
+	n.chords2intervals["Dom7#9"] = append(n.chords2intervals["Dom7#9"], "1", "3", "5", "b7", "#9")
+	n.chords2intervals["Dom7#11"] = append(n.chords2intervals["Dom7#11"], "1", "3", "5", "b7", "#11")
+	n.chords2intervals["Dom7b13"] = append(n.chords2intervals["Dom7b13"], "1", "3", "5", "b7", "b13")
 	n.chords2intervals["Sus2"] = append(n.chords2intervals["Sus2"], "1", "2", "5")
 	n.chords2intervals["Sus4"] = append(n.chords2intervals["Sus4"], "1", "4", "5")
-	n.chords2intervals["Add9"] = append(n.chords2intervals["Add9"], "1", "3", "5", "2")
+	n.chords2intervals["Add9"] = append(n.chords2intervals["Add9"], "1", "3", "5", "9")
+
+	// Definitions loaded with Define replace built-in ones of the same name.
+	n.aliases = make(map[string][]string)
+	for name, d := range defined {
+		n.chords2intervals[name] = append([]string(nil), d.Intervals...)
+		n.aliases[name] = d.Aliases
+	}
 
+	// Chords that share their intervals keep the first name in
+	// alphabetical order, so the index is the same on every run.
+	var names []string
+	for chord := range n.chords2intervals {
+		names = append(names, chord)
+	}
+	sort.Strings(names)
 	n.intervals2chords = make(map[int]string)
-	for chord, v := range n.chords2intervals {
+	for _, chord := range names {
 		m := 0
-		for _, intr := range v {
+		for _, intr := range n.chords2intervals[chord] {
 			i, _ := n.interval.IntervalToOffset(intr)
 			m = m | 1<<(11-i)
 		}
-		n.intervals2chords[m] = chord
-	}
-	for key, value := range n.interval.GetOffset() {
-		fmt.Printf("")
-		fmt.Printf("Key: %s, 0x%x\n", key, 1<<(11-value))
+		if _, ok := n.intervals2chords[m]; !ok {
+			n.intervals2chords[m] = chord
+		}
 	}
 	allnotes := []note.Note{
 		{Name: "A", Alternate: root.Alternate},
@@ -78,17 +102,110 @@ func NewChord(root note.Note) *Chord {
 		n.notes = n.notes.Next()
 	}
 
-	n.SetRoot(root)
+	if err := n.SetRoot(root); err != nil {
+		return nil, err
+	}
 
-	return n
+	return n, nil
 }
 
-func (n *Chord) SetRoot(root note.Note) {
+// SetRoot moves the root to the note named as root is. The root keeps the
+// spelling it was given, such as Cb in place of B.
+func (n *Chord) SetRoot(root note.Note) error {
+	if !root.Valid() {
+		return fmt.Errorf("unknown root note %q", root.Name)
+	}
 	for i := 0; i < n.notes.Len(); i++ {
-		if n.notes.Value == root {
+		if n.notes.Value.(note.Note).Name == root.Name {
+			n.notes.Value = root
 			n.root = n.notes
 			break
 		}
 		n.notes = n.notes.Next()
 	}
+	return nil
+}
+
+// Root returns the root note of the chords.
+func (n *Chord) Root() note.Note {
+	return n.root.Value.(note.Note)
+}
+
+func (n *Chord) ChordNames() []string {
+	var chordNames []string
+	for name := range n.chords2intervals {
+		chordNames = append(chordNames, name)
+	}
+	sort.Strings(chordNames)
+	return chordNames
+}
+
+// Lookup returns the name of the chord called name or known by it as an
+// alias, ignoring case.
+func (n *Chord) Lookup(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, chordName := range n.ChordNames() {
+		if strings.EqualFold(chordName, name) {
+			return chordName, true
+		}
+	}
+	for _, chordName := range n.ChordNames() {
+		for _, alias := range n.aliases[chordName] {
+			if strings.EqualFold(alias, name) {
+				return chordName, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Aliases returns the other names of the chord called name.
+func (n *Chord) Aliases(name string) []string {
+	return n.aliases[name]
+}
+
+func (n *Chord) ChordNotes(name string) []note.Note {
+	var notes []note.Note
+
+	for _, chordNote := range n.chords2intervals[name] {
+		notes = append(notes, n.ShowNoteAt(chordNote))
+	}
+	return notes
+}
+
+// ChordIntervals returns the intervals of the chord called name, from the
+// root, or nil when there is no such chord.
+func (n *Chord) ChordIntervals(name string) []string {
+	var inter []string
+
+	for _, chordNote := range n.chords2intervals[name] {
+		inter = append(inter, chordNote)
+	}
+	return inter
+}
+
+// ShowNoteAt returns the note interval above the root, spelled with the
+// letter of the interval's degree.
+func (n *Chord) ShowNoteAt(interval string) note.Note {
+
+	offset, err := n.interval.IntervalToOffset(interval)
+	if err != nil {
+		fmt.Println(err.Error())
+		return note.Note{}
+	}
+	degree, err := n.interval.Degree(interval)
+	if err != nil {
+		fmt.Println(err.Error())
+		return note.Note{}
+	}
+
+	return n.Root().Spell(degree, offset)
+}
+
+func (n *Chord) GetChordNotes(chordName string) string {
+	var sb strings.Builder
+	for _, chordNote := range n.chords2intervals[chordName] {
+		sb.WriteString(n.ShowNoteAt(chordNote).String())
+	}
+	return sb.String()
 }
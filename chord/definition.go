@@ -0,0 +1,26 @@
+package chord
+
+import (
+	"errors"
+
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+// defined holds the chords added with Define, by name.
+var defined = make(map[string]scale.Definition)
+
+// Define adds chords to the ones every new Chord knows, replacing any built-in
+// chord of the same name. Nothing is added unless every definition is valid.
+func Define(defs ...scale.Definition) error {
+	var errs []error
+	for _, d := range defs {
+		errs = append(errs, d.Validate())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	for _, d := range defs {
+		defined[d.Name] = d
+	}
+	return nil
+}
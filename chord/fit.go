@@ -0,0 +1,112 @@
+package chord
+
+import (
+	"sort"
+
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+// ScaleFit is a scale that holds every tone of a chord built on its root.
+type ScaleFit struct {
+	Scale string
+	// Avoid lists the scale's notes a half step above a chord tone, which
+	// clash with the chord when held over it.
+	Avoid []note.Note
+}
+
+// ChordFit is a chord whose every tone is in a scale.
+type ChordFit struct {
+	Root note.Note
+	Name string
+	// Degree counts the scale's notes from 1 up to the chord's root.
+	Degree int
+}
+
+// ScalesFor returns the scales on the chord's root that hold every tone of
+// the chord called name, those with the fewest avoid notes first.
+func (n *Chord) ScalesFor(name string) []ScaleFit {
+	chordPitches := n.pitches(n.chords2intervals[name])
+	if len(chordPitches) == 0 {
+		return nil
+	}
+
+	s, err := scale.NewScale(n.Root())
+	if err != nil {
+		return nil
+	}
+	var fits []ScaleFit
+	for _, scaleName := range s.ScaleNames() {
+		scalePitches := n.pitches(s.ScaleInterval(scaleName))
+		if !contains(scalePitches, chordPitches) {
+			continue
+		}
+
+		fit := ScaleFit{Scale: scaleName}
+		for _, intr := range s.ScaleInterval(scaleName) {
+			offset, err := n.interval.IntervalToOffset(intr)
+			if err != nil {
+				continue
+			}
+			if !chordPitches[offset] && chordPitches[(offset+11)%12] {
+				fit.Avoid = append(fit.Avoid, s.ShowNoteAt(intr))
+			}
+		}
+		fits = append(fits, fit)
+	}
+
+	sort.SliceStable(fits, func(i, j int) bool {
+		return len(fits[i].Avoid) < len(fits[j].Avoid)
+	})
+	return fits
+}
+
+// ChordsIn returns every chord built on a note of the scale called
+// scaleName whose tones are all in the scale, in the order of the scale's
+// degrees and then by chord name.
+func ChordsIn(s *scale.Scale, scaleName string) []ChordFit {
+	var fits []ChordFit
+	notes := s.ScaleNotes(scaleName)
+	scalePitches := make(map[int]bool)
+	for _, no := range notes {
+		scalePitches[no.PitchClass()] = true
+	}
+
+	for degree, root := range notes {
+		c, err := NewChord(root)
+		if err != nil {
+			continue
+		}
+		for _, name := range c.ChordNames() {
+			chordPitches := make(map[int]bool)
+			for pitch := range c.pitches(c.chords2intervals[name]) {
+				chordPitches[(pitch+root.PitchClass())%12] = true
+			}
+			if contains(scalePitches, chordPitches) {
+				fits = append(fits, ChordFit{Root: c.Root(), Name: name, Degree: degree + 1})
+			}
+		}
+	}
+	return fits
+}
+
+// pitches returns the semitones above the root of intervals.
+func (n *Chord) pitches(intervals []string) map[int]bool {
+	pitches := make(map[int]bool)
+	for _, intr := range intervals {
+		if offset, err := n.interval.IntervalToOffset(intr); err == nil {
+			pitches[offset%12] = true
+		}
+	}
+	return pitches
+}
+
+// contains reports whether every pitch of sub is in set.
+func contains(set, sub map[int]bool) bool {
+	for pitch := range sub {
+		if !set[pitch] {
+			return false
+		}
+	}
+	return true
+}
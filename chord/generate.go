@@ -0,0 +1,263 @@
+package chord
+
+import (
+	"sort"
+
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+// VoicingOption adjusts the search done by Voicings.
+type VoicingOption func(*voicingSearch)
+
+type voicingSearch struct {
+	maxSpan    int
+	maxFret    int
+	rootInBass bool
+	limit      int
+}
+
+// WithMaxSpan limits the fretted notes of a voicing to span frets, 4 by
+// default.
+func WithMaxSpan(span int) VoicingOption {
+	return func(vs *voicingSearch) {
+		vs.maxSpan = max(span, 1)
+	}
+}
+
+// WithMaxFret limits the search to the frets up to fret, 12 by default.
+func WithMaxFret(fret int) VoicingOption {
+	return func(vs *voicingSearch) {
+		vs.maxFret = max(fret, 1)
+	}
+}
+
+// WithRootInBass only keeps voicings whose lowest note is the root.
+func WithRootInBass() VoicingOption {
+	return func(vs *voicingSearch) {
+		vs.rootInBass = true
+	}
+}
+
+// WithLimit returns at most n voicings, the easiest first. Every voicing
+// found is returned by default.
+func WithLimit(n int) VoicingOption {
+	return func(vs *voicingSearch) {
+		vs.limit = n
+	}
+}
+
+// Voicings searches the fretboard of an instrument tuned to t for playable
+// voicings of the chord called name and returns them easiest first, with
+// the finger for every fretted string.
+//
+// A voicing plays every chord tone, though the fifth may be left out of
+// chords with four or more, on at least three strings (or every string of
+// smaller instruments). Its fretted notes fit within the span, it needs at
+// most four fingers with the first finger as the only barre, and it mutes
+// no more than one string between the strings it plays.
+func (n *Chord) Voicings(name string, t tuning.Tuning, opts ...VoicingOption) []Voicing {
+	return n.VoicingsOf(n.chords2intervals[name], t, opts...)
+}
+
+// VoicingsOf is Voicings for the chord of intervals above the root, which
+// need not have a name.
+func (n *Chord) VoicingsOf(intervals []string, t tuning.Tuning, opts ...VoicingOption) []Voicing {
+	vs := voicingSearch{maxSpan: 4, maxFret: 12}
+	for _, opt := range opts {
+		opt(&vs)
+	}
+
+	if intervals == nil || len(t.Strings) == 0 {
+		return nil
+	}
+
+	// The pitch classes of the chord, and which of them must be played
+	rootPitch := n.root.Value.(note.Note).PitchClass()
+	tones := make(map[int]bool)
+	required := make(map[int]bool)
+	for _, intr := range intervals {
+		offset, err := n.interval.IntervalToOffset(intr)
+		if err != nil {
+			continue
+		}
+		pitch := (rootPitch + offset) % 12
+		tones[pitch] = true
+		required[pitch] = intr != "5" || len(intervals) < 4
+	}
+	minStrings := min(max(3, len(required)), len(t.Strings))
+
+	type scored struct {
+		v     Voicing
+		score float64
+	}
+	var found []scored
+	seen := make(map[string]bool)
+
+	frets := make([]int, len(t.Strings))
+	var search func(s, low int)
+	search = func(s, low int) {
+		if s < len(t.Strings) {
+			frets[s] = Muted
+			search(s+1, low)
+			for fret := 0; fret <= min(low+vs.maxSpan-1, vs.maxFret); fret++ {
+				if fret > 0 && fret < low {
+					continue
+				}
+				if tones[(t.Strings[s].PitchClass()+fret)%12] {
+					frets[s] = fret
+					search(s+1, low)
+				}
+			}
+			return
+		}
+
+		v := Voicing{Frets: append([]int(nil), frets...)}
+		key := v.String()
+		if seen[key] {
+			return
+		}
+		score, ok := vs.rate(&v, t, tones, required, rootPitch, minStrings)
+		if !ok {
+			return
+		}
+		seen[key] = true
+		found = append(found, scored{v, score})
+	}
+	for low := 1; low <= vs.maxFret; low++ {
+		search(0, low)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].score != found[j].score {
+			return found[i].score < found[j].score
+		}
+		return found[i].v.String() < found[j].v.String()
+	})
+
+	var voicings []Voicing
+	for _, f := range found {
+		if vs.limit > 0 && len(voicings) == vs.limit {
+			break
+		}
+		voicings = append(voicings, f.v)
+	}
+	return voicings
+}
+
+// rate checks that v is a complete, playable voicing, fingers it and
+// returns how hard it is to play, lower being easier.
+func (vs voicingSearch) rate(v *Voicing, t tuning.Tuning, tones, required map[int]bool, rootPitch, minStrings int) (float64, bool) {
+	played := make(map[int]bool)
+	first, last, sounding := -1, -1, 0
+	for s, fret := range v.Frets {
+		if fret == Muted {
+			continue
+		}
+		played[(t.Strings[s].PitchClass()+fret)%12] = true
+		if first < 0 {
+			first = s
+		}
+		last = s
+		sounding++
+	}
+	if sounding < minStrings {
+		return 0, false
+	}
+	for pitch, must := range required {
+		if must && !played[pitch] {
+			return 0, false
+		}
+	}
+
+	bass := (t.Strings[first].PitchClass() + v.Frets[first]) % 12
+	if vs.rootInBass && bass != rootPitch {
+		return 0, false
+	}
+
+	innerMuted := (last - first + 1) - sounding
+	if innerMuted > 1 {
+		return 0, false
+	}
+
+	low, high := v.FretSpan()
+	if high-low+1 > vs.maxSpan {
+		return 0, false
+	}
+
+	barre, ok := v.Fingering()
+	if !ok {
+		return 0, false
+	}
+
+	fingers := 0
+	for _, f := range v.Fingers {
+		fingers = max(fingers, f)
+	}
+	score := float64(high-low) + float64(fingers) + 0.25*float64(low) +
+		3*float64(innerMuted) + 1.5*float64(len(v.Frets)-sounding)
+	if barre {
+		score += 2
+	}
+	if bass != rootPitch {
+		score += 2
+	}
+	return score, true
+}
+
+// Fingering works out the fingers of v: a first finger barre across the
+// lowest fret when more than one string is held there, and the next finger
+// for each other fretted note from the lowest fret up. It reports whether
+// a barre is used and false, leaving no fingers, when the voicing needs
+// more than four fingers or a barre over an open string.
+func (v *Voicing) Fingering() (barre, ok bool) {
+	low, _ := v.FretSpan()
+	v.Fingers = make([]int, len(v.Frets))
+	if low == 0 {
+		return false, true
+	}
+
+	var atLow []int
+	for s, fret := range v.Frets {
+		if fret == low {
+			atLow = append(atLow, s)
+		}
+	}
+	barre = len(atLow) > 1
+	if barre {
+		// The barre lies across every string from the first to the last it
+		// holds, so none of those may ring open.
+		for s := atLow[0]; s <= atLow[len(atLow)-1]; s++ {
+			if v.Frets[s] == 0 {
+				v.Fingers = nil
+				return false, false
+			}
+		}
+	}
+
+	type held struct{ s, fret int }
+	var notes []held
+	for s, fret := range v.Frets {
+		if fret > 0 && !(barre && fret == low) {
+			notes = append(notes, held{s, fret})
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].fret < notes[j].fret })
+
+	finger := 1
+	if barre {
+		for _, s := range atLow {
+			v.Fingers[s] = 1
+		}
+		finger = 2
+	}
+	for _, n := range notes {
+		if finger > 4 {
+			v.Fingers = nil
+			return false, false
+		}
+		v.Fingers[n.s] = finger
+		finger++
+	}
+	return barre, true
+}
@@ -0,0 +1,137 @@
+package chord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+// DiatonicChord is the chord built by stacking thirds within a scale on
+// one of its degrees.
+type DiatonicChord struct {
+	// Degree counts the scale's notes from 1 for its root.
+	Degree int
+	Root   note.Note
+	// Numeral is the Roman numeral of the degree, upper case for major and
+	// lower case for minor chords, e.g. "ii", "V7" or "vii°".
+	Numeral string
+	// Name is the chord package's name for the chord, "" when it has none.
+	Name      string
+	Intervals []string
+	Notes     []note.Note
+}
+
+// chordToneNames names the semitones above a chord's root the way
+// chords2intervals does.
+var chordToneNames = []string{"1", "b2", "2", "b3", "3", "4", "b5", "5", "#5", "6", "b7", "7"}
+
+// Harmonize builds a chord of tones notes, 3 for triads or 4 for seventh
+// chords, on every degree of the scale called scaleName by stacking every
+// other note of the scale on it. Only seven note scales stack into thirds,
+// so other scales are an error.
+func Harmonize(s *scale.Scale, scaleName string, tones int) ([]DiatonicChord, error) {
+	if tones != 3 && tones != 4 {
+		return nil, fmt.Errorf("harmonize with 3 or 4 notes, not %d", tones)
+	}
+	notes := s.ScaleNotes(scaleName)
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("unknown scale %q", scaleName)
+	}
+	if len(notes) != 7 {
+		return nil, fmt.Errorf("%s has %d notes, only seven note scales stack into thirds", scaleName, len(notes))
+	}
+
+	var chords []DiatonicChord
+	for degree, root := range notes {
+		c, err := NewChord(root)
+		if err != nil {
+			return nil, err
+		}
+		dc := DiatonicChord{Degree: degree + 1, Root: c.Root()}
+
+		mask := 0
+		var offsets []int
+		for i := 0; i < tones; i++ {
+			no := notes[(degree+2*i)%len(notes)]
+			offset := (no.PitchClass() - root.PitchClass() + 12) % 12
+			offsets = append(offsets, offset)
+			mask |= 1 << (11 - offset)
+			// Stacked thirds land on the odd degrees, so the 7th of a
+			// diminished seventh chord is written bb7.
+			intr := chordToneNames[offset]
+			if name, err := c.interval.Name(2*i+1, offset); err == nil {
+				intr = name
+			}
+			dc.Intervals = append(dc.Intervals, intr)
+			dc.Notes = append(dc.Notes, c.ShowNoteAt(intr))
+		}
+		dc.Name = c.intervals2chords[mask]
+		dc.Numeral = numeral(degree+1, offsets)
+		chords = append(chords, dc)
+	}
+	return chords, nil
+}
+
+// numeral writes degree as a Roman numeral with the quality of the chord
+// whose tones lie offsets semitones above its root: upper case for a major
+// third and lower case for a minor one, ° for diminished, + for augmented,
+// ø7 for half diminished, and any other fifth or seventh in brackets, as in
+// "V7(b5)".
+func numeral(degree int, offsets []int) string {
+	romans := []string{"I", "II", "III", "IV", "V", "VI", "VII"}
+	roman := romans[degree-1]
+	interval := scale.NewInterval()
+	altered := func(degree, semitones int) string {
+		name, err := interval.Name(degree, semitones)
+		if err != nil {
+			name = chordToneNames[semitones]
+		}
+		return "(" + name + ")"
+	}
+
+	third, fifth := offsets[1], offsets[2]
+	var base, alterations string
+	switch third {
+	case 4:
+		base = roman
+	case 3:
+		base = strings.ToLower(roman)
+	case 2:
+		base = roman + "sus2"
+	case 5:
+		base = roman + "sus4"
+	default:
+		base = roman + altered(3, third)
+	}
+	switch {
+	case fifth == 7:
+	case fifth == 6 && third == 3:
+		base += "°"
+	case fifth == 8 && third == 4:
+		base += "+"
+	default:
+		alterations = altered(5, fifth)
+	}
+	if len(offsets) < 4 {
+		return base + alterations
+	}
+
+	switch seventh := offsets[3]; {
+	case seventh == 11 && third == 3:
+		return base + "(maj7)" + alterations
+	case seventh == 11:
+		return base + "maj7" + alterations
+	case seventh == 10 && strings.HasSuffix(base, "°"):
+		return strings.TrimSuffix(base, "°") + "ø7" + alterations
+	case seventh == 10:
+		return base + "7" + alterations
+	case seventh == 9 && strings.HasSuffix(base, "°"):
+		return base + "7" + alterations
+	case seventh == 9 && fifth == 7:
+		// A diminished seventh over a perfect fifth is heard as a sixth.
+		return base + "6" + alterations
+	}
+	return base + alterations + altered(7, offsets[3])
+}
@@ -0,0 +1,65 @@
+package chord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+func TestHarmonizeNumerals(t *testing.T) {
+	tests := []struct {
+		scale string
+		tones int
+		want  string
+	}{
+		{"ionian", 3, "I ii iii IV V vi vii°"},
+		{"ionian", 4, "Imaj7 ii7 iii7 IVmaj7 V7 vi7 viiø7"},
+		{"harmonic minor", 4, "i(maj7) iiø7 III+maj7 iv7 V7 VImaj7 vii°7"},
+		{"egyptian", 3, ""},
+	}
+	s, err := scale.NewScale(note.Note{Name: "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		chords, err := Harmonize(s, tt.scale, tt.tones)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Harmonize(%q) = nil error, want one for a scale without seven notes", tt.scale)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var numerals []string
+		for _, dc := range chords {
+			numerals = append(numerals, dc.Numeral)
+		}
+		if got := strings.Join(numerals, " "); got != tt.want {
+			t.Errorf("Harmonize(%q, %d) numerals = %q, want %q", tt.scale, tt.tones, got, tt.want)
+		}
+	}
+}
+
+func TestNumeralNeverUnknown(t *testing.T) {
+	s, err := scale.NewScale(note.Note{Name: "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range s.ScaleNames() {
+		for _, tones := range []int{3, 4} {
+			chords, err := Harmonize(s, name, tones)
+			if err != nil {
+				continue
+			}
+			for _, dc := range chords {
+				if strings.Contains(dc.Numeral, "?") {
+					t.Errorf("%s degree %d: numeral %q", name, dc.Degree, dc.Numeral)
+				}
+			}
+		}
+	}
+}
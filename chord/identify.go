@@ -0,0 +1,174 @@
+package chord
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+// Match is one way to name a set of notes as a chord.
+type Match struct {
+	Root note.Note
+	// Name is the chord's name in the chord package, e.g. "Major7th".
+	Name string
+	// Bass is the lowest note. It is the root in root position, another
+	// chord tone in an inversion or a note outside the chord in a slash
+	// chord.
+	Bass note.Note
+	// Inversion counts the thirds from the root up to the bass: 0 in root
+	// position, 1 with the third in the bass, 2 with the fifth, 3 with the
+	// seventh and so on. It is -1 when the bass is not a chord tone.
+	Inversion int
+	// Omitted lists the chord tones that were not played.
+	Omitted []string
+}
+
+// String names the match the way it is usually written, e.g. "C Major",
+// "C Major/E" or "C Dom7th (no 5)".
+func (m Match) String() string {
+	s := strings.TrimSpace(m.Root.String()) + " " + m.Name
+	if m.Bass.Name != m.Root.Name {
+		s += "/" + strings.TrimSpace(m.Bass.String())
+	}
+	if len(m.Omitted) > 0 {
+		s += " (no " + strings.Join(m.Omitted, ", ") + ")"
+	}
+	return s
+}
+
+// rank orders matches by how plainly they name the notes: root position
+// before inversions, complete chords before omitted fifths and chord tones
+// in the bass before slash chords.
+func (m Match) rank() int {
+	r := 2 * len(m.Omitted)
+	switch {
+	case m.Inversion < 0:
+		r += 3
+	case m.Inversion > 0:
+		r++
+	}
+	return r
+}
+
+// Identify names the chords formed by notes, whose first note is the bass,
+// and returns the matches best first. Every note is tried as the root,
+// with the fifth allowed to be missing, and the bass may lie outside the
+// chord as in a slash chord. Notes are spelled as the chord's root is.
+func (n *Chord) Identify(notes []note.Note) []Match {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	pitches := make(map[int]bool)
+	for _, no := range notes {
+		pitches[no.PitchClass()] = true
+	}
+	bass := notes[0].PitchClass()
+
+	var matches []Match
+	seen := make(map[string]bool)
+	add := func(m Match) {
+		if key := m.String(); !seen[key] {
+			seen[key] = true
+			matches = append(matches, m)
+		}
+	}
+
+	for root := range pitches {
+		// The notes as they are, then without the bass for a slash chord
+		// over a note outside the chord.
+		for _, withBass := range []bool{true, false} {
+			if !withBass && (root == bass || len(pitches) < 4) {
+				continue
+			}
+			mask := 0
+			for pitch := range pitches {
+				if withBass || pitch != bass {
+					mask |= 1 << (11 - (pitch-root+12)%12)
+				}
+			}
+
+			if name, ok := n.intervals2chords[mask]; ok {
+				add(n.match(name, root, bass, nil))
+			}
+			// A fifth is often left out of larger chords.
+			fifth := 1 << (11 - 7)
+			if mask&fifth == 0 {
+				if name, ok := n.intervals2chords[mask|fifth]; ok && len(n.chords2intervals[name]) > 3 {
+					add(n.match(name, root, bass, []string{"5"}))
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank() != matches[j].rank() {
+			return matches[i].rank() < matches[j].rank()
+		}
+		if len(n.chords2intervals[matches[i].Name]) != len(n.chords2intervals[matches[j].Name]) {
+			return len(n.chords2intervals[matches[i].Name]) < len(n.chords2intervals[matches[j].Name])
+		}
+		return matches[i].String() < matches[j].String()
+	})
+	return matches
+}
+
+// IdentifyVoicing names the chords played by voicing v on an instrument
+// tuned to t, taking the lowest string played as the bass.
+func (n *Chord) IdentifyVoicing(v Voicing, t tuning.Tuning) []Match {
+	return n.Identify(n.VoicingNotes(v, t))
+}
+
+// VoicingNotes returns the notes voicing v plays on an instrument tuned to
+// t, from the lowest string, spelled as the chord's root is.
+func (n *Chord) VoicingNotes(v Voicing, t tuning.Tuning) []note.Note {
+	var notes []note.Note
+	for s, fret := range v.Frets {
+		if fret == Muted || s >= len(t.Strings) {
+			continue
+		}
+		notes = append(notes, n.noteAt(t.Strings[s].PitchClass()+fret))
+	}
+	return notes
+}
+
+// match builds the Match of the chord called name on root over bass.
+func (n *Chord) match(name string, root, bass int, omitted []string) Match {
+	m := Match{Root: n.noteAt(root), Name: name, Bass: n.noteAt(bass), Inversion: -1, Omitted: omitted}
+	for _, intr := range n.chords2intervals[name] {
+		offset, err := n.interval.IntervalToOffset(intr)
+		if err != nil || (root+offset)%12 != bass {
+			continue
+		}
+		if degree, err := n.interval.Degree(intr); err == nil {
+			m.Inversion = inversion(degree)
+			break
+		}
+	}
+	return m
+}
+
+// inversion returns the inversion with the chord tone on degree in the
+// bass. The 2nd and 4th of sus chords stand in for the 3rd and the 6th for
+// the 7th, and a compound even degree is the odd one an octave below it,
+// as the 10th is the 3rd.
+func inversion(degree int) int {
+	if degree > 7 && degree%2 == 0 {
+		degree -= 7
+	}
+	switch degree {
+	case 2, 4:
+		return 1
+	case 6:
+		return 3
+	}
+	return (degree - 1) / 2
+}
+
+// noteAt returns the note of pitch class pitch, spelled as the root is.
+func (n *Chord) noteAt(pitch int) note.Note {
+	rootPitch := n.root.Value.(note.Note).PitchClass()
+	return n.root.Move(((pitch-rootPitch)%12 + 12) % 12).Value.(note.Note)
+}
@@ -0,0 +1,47 @@
+package chord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mrgrenier/GuitarScales/note"
+)
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		notes     string
+		want      string
+		inversion int
+	}{
+		{"C E G", "C Major", 0},
+		{"E G C", "C Major/E", 1},
+		{"Bb C G D F", "C Dom11th/Bb", 3},
+		{"C E G Bb Db", "C Dom7b9", 0},
+		{"C E G Bb D#", "C Dom7#9", 0},
+		{"C E G Bb F#", "C Dom7#11", 0},
+		{"C E G Bb Ab", "C Dom7b13", 0},
+		{"C E G B F#", "C Major7#11", 0},
+	}
+	for _, tt := range tests {
+		var notes []note.Note
+		for _, name := range strings.Fields(tt.notes) {
+			n, err := note.Parse(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			notes = append(notes, n)
+		}
+		c, err := NewChord(notes[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		matches := c.Identify(notes)
+		if len(matches) == 0 {
+			t.Errorf("Identify(%s) found nothing, want %s", tt.notes, tt.want)
+			continue
+		}
+		if got := matches[0]; got.String() != tt.want || got.Inversion != tt.inversion {
+			t.Errorf("Identify(%s) = %s inversion %d, want %s inversion %d", tt.notes, got, got.Inversion, tt.want, tt.inversion)
+		}
+	}
+}
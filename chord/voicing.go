@@ -0,0 +1,91 @@
+package chord
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Muted marks a string that is not played in a Voicing.
+const Muted = -1
+
+// Voicing is one way to play a chord on a fretted instrument: the fret held
+// on each string from the lowest, 0 for an open string or Muted, and the
+// finger holding it (1 index to 4 little, 0 for none or unknown).
+type Voicing struct {
+	Frets   []int
+	Fingers []int
+}
+
+// ParseVoicing parses the frets of a voicing from the lowest string, written
+// as one character per string ("x32010") or separated by spaces, commas or
+// dashes when frets go past 9 ("x-10-12-12-11-10"). An x marks a muted
+// string.
+func ParseVoicing(s string) (Voicing, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '-' || r == '\t'
+	})
+	if len(fields) == 1 {
+		fields = strings.Split(fields[0], "")
+	}
+	if len(fields) == 0 {
+		return Voicing{}, fmt.Errorf("voicing %q: no strings", s)
+	}
+
+	var v Voicing
+	for _, f := range fields {
+		if strings.EqualFold(f, "x") {
+			v.Frets = append(v.Frets, Muted)
+			continue
+		}
+		fret, err := strconv.Atoi(f)
+		if err != nil || fret < 0 {
+			return Voicing{}, fmt.Errorf("voicing %q: bad fret %q", s, f)
+		}
+		v.Frets = append(v.Frets, fret)
+	}
+	return v, nil
+}
+
+// String writes the voicing the way ParseVoicing reads it.
+func (v Voicing) String() string {
+	sep := ""
+	for _, fret := range v.Frets {
+		if fret > 9 {
+			sep = "-"
+		}
+	}
+
+	parts := make([]string, len(v.Frets))
+	for i, fret := range v.Frets {
+		if fret == Muted {
+			parts[i] = "x"
+		} else {
+			parts[i] = strconv.Itoa(fret)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// FretSpan returns the lowest and highest fretted (not open or muted) frets
+// of the voicing, or 0, 0 when no string is fretted.
+func (v Voicing) FretSpan() (low, high int) {
+	for _, fret := range v.Frets {
+		if fret <= 0 {
+			continue
+		}
+		if low == 0 || fret < low {
+			low = fret
+		}
+		high = max(high, fret)
+	}
+	return low, high
+}
+
+// Finger returns the finger on string s, or 0 when none is known.
+func (v Voicing) Finger(s int) int {
+	if s < len(v.Fingers) {
+		return v.Fingers[s]
+	}
+	return 0
+}
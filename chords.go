@@ -0,0 +1,302 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrgrenier/GuitarScales/chord"
+	"github.com/mrgrenier/GuitarScales/diagram"
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+// runChords draws chord boxes for the voicings given as arguments, e.g.
+// "chords -root C -chord Major x32010 x35553", or for the easiest voicings
+// found on the fretboard when none are given.
+func runChords(args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("chords", flag.ExitOnError)
+	fs.StringVar(&opts.root, "root", "C", "root note of the chord, e.g. C, F#, Bb")
+	fs.StringVar(&opts.accidental, "accidental", "", "spell notes with sharp or flat (default: from -root, flat for naturals)")
+	chordName := fs.String("chord", "Major", "chord to draw, e.g. Major, Minor7th, Sus4")
+	fs.StringVar(&opts.instrument, "instrument", "guitar", "fretted instrument: "+strings.Join(tuning.InstrumentNames(), ", "))
+	tuningSpec := fs.String("tuning", "", "tuning of the instrument: a preset ("+strings.Join(tuning.Names(), ", ")+") or notes from the lowest string (default: the instrument's usual tuning)")
+	fs.StringVar(&opts.outDir, "out", "./output", "output directory")
+	fs.StringVar(&opts.format, "format", "both", "output format: png, pdf or both")
+	count := fs.Int("voicings", 8, "number of voicings to find when none are given")
+	rootInBass := fs.Bool("root-bass", false, "only find voicings with the root as the lowest note")
+	maxFret := fs.Int("max-fret", 12, "highest fret used by the voicings found")
+	addLibraryFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: GuitarScales chords [flags] [voicing...]\n\nEach voicing lists the frets from the lowest string, x for muted: x32010, or x-10-12-12-11-10 past fret 9.\nWithout voicings, the easiest ones found on the fretboard are drawn.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	inst := findInstrument(opts.instrument)
+	if inst.fretted == nil {
+		return fmt.Errorf("chords are drawn for fretted instruments, not %q", opts.instrument)
+	}
+	var err error
+	if *tuningSpec != "" {
+		if opts.tuning, err = tuning.Parse(*tuningSpec); err != nil {
+			return err
+		}
+	}
+	t := inst.tuning(opts)
+
+	root, err := parseRoot(opts.root, opts.accidental)
+	if err != nil {
+		return err
+	}
+	writePNG, writePDF, err := parseFormat(opts.format)
+	if err != nil {
+		return err
+	}
+
+	c, err := chord.NewChord(root)
+	if err != nil {
+		return err
+	}
+	name, err := findChord(c, *chordName)
+	if err != nil {
+		return err
+	}
+	intervals := c.ChordIntervals(name)
+	title := keyName(root) + " " + name
+
+	var voicings []chord.Voicing
+	for _, arg := range fs.Args() {
+		v, err := chord.ParseVoicing(arg)
+		if err != nil {
+			return err
+		}
+		if len(v.Frets) != len(t.Strings) {
+			return fmt.Errorf("voicing %q has %d strings, %s has %d", arg, len(v.Frets), inst.name, len(t.Strings))
+		}
+		// Voicings that need more than four fingers are drawn without them.
+		v.Fingering()
+		voicings = append(voicings, v)
+	}
+	if len(voicings) == 0 {
+		search := []chord.VoicingOption{chord.WithLimit(*count), chord.WithMaxFret(*maxFret)}
+		if *rootInBass {
+			search = append(search, chord.WithRootInBass())
+		}
+		if voicings = c.Voicings(name, t, search...); len(voicings) == 0 {
+			return fmt.Errorf("no playable voicings of %s found on %s", title, inst.name)
+		}
+	}
+
+	pngDir := filepath.Join(opts.outDir, inst.name, "chords")
+	if !writePNG {
+		if pngDir, err = os.MkdirTemp("", inst.name+"-chords"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(pngDir)
+	}
+	if err := os.MkdirAll(pngDir, 0o755); err != nil {
+		return err
+	}
+
+	var d diagram.Diagram
+	var pngPaths []string
+	for i, v := range voicings {
+		if d, err = diagram.NewChordDiagram(v, diagram.WithChordRoot(root), diagram.WithChordTuning(t)); err != nil {
+			return err
+		}
+		d.DrawDiagram()
+		d.ColorScale(intervals)
+		d.DrawTitle(title+" ("+v.String()+")", c.GetChordNotes(name), 40, inst.titleY)
+		pngPath := filepath.Join(pngDir, fmt.Sprintf("%s - %02d %s.png", title, i+1, v))
+		d.SaveScaleDiagram(pngPath)
+		pngPaths = append(pngPaths, pngPath)
+	}
+
+	if writePDF {
+		return d.TileSectionsToPDF([]diagram.PDFSection{{Dir: pngDir, Files: pngPaths}}, filepath.Join(opts.outDir, inst.name+"_chords.pdf"))
+	}
+	return nil
+}
+
+// findChord returns the chord package's name for the chord asked for by
+// name or alias, ignoring case.
+func findChord(c *chord.Chord, name string) (string, error) {
+	if chordName, ok := c.Lookup(name); ok {
+		return chordName, nil
+	}
+	return "", fmt.Errorf("unknown chord %q, want one of %s", name, strings.Join(c.ChordNames(), ", "))
+}
+
+// runIdentify names the chord formed by the notes given as arguments,
+// lowest first, e.g. "identify E G C", or by the frets given with -frets.
+// With -scales it lists the scales that hold the notes instead.
+func runIdentify(args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("identify", flag.ExitOnError)
+	fs.StringVar(&opts.accidental, "accidental", "", "spell notes with sharp or flat (default: from the notes, flat for naturals)")
+	frets := fs.String("frets", "", "frets of a voicing from the lowest string, e.g. x32010, instead of notes")
+	fs.StringVar(&opts.instrument, "instrument", "guitar", "fretted instrument for -frets: "+strings.Join(tuning.InstrumentNames(), ", "))
+	tuningSpec := fs.String("tuning", "", "tuning for -frets: a preset ("+strings.Join(tuning.Names(), ", ")+") or notes from the lowest string (default: the instrument's usual tuning)")
+	scales := fs.Bool("scales", false, "list the scales that hold the notes instead of naming a chord")
+	limit := fs.Int("limit", 20, "most scales listed with -scales, 0 for all")
+	addLibraryFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: GuitarScales identify [flags] [note...]\n\nThe notes are listed from the lowest, which is taken as the bass.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var matches []chord.Match
+	if *frets != "" {
+		inst := findInstrument(opts.instrument)
+		if inst.fretted == nil {
+			return fmt.Errorf("-frets needs a fretted instrument, not %q", opts.instrument)
+		}
+		var err error
+		if *tuningSpec != "" {
+			if opts.tuning, err = tuning.Parse(*tuningSpec); err != nil {
+				return err
+			}
+		}
+		v, err := chord.ParseVoicing(*frets)
+		if err != nil {
+			return err
+		}
+		t := inst.tuning(opts)
+		if len(v.Frets) != len(t.Strings) {
+			return fmt.Errorf("voicing %q has %d strings, %s has %d", *frets, len(v.Frets), inst.name, len(t.Strings))
+		}
+		spelling, err := parseRoot("C", opts.accidental)
+		if err != nil {
+			return err
+		}
+		c, err := chord.NewChord(spelling)
+		if err != nil {
+			return err
+		}
+		if *scales {
+			return printScaleMatches(c.VoicingNotes(v, t), *limit)
+		}
+		matches = c.IdentifyVoicing(v, t)
+	} else {
+		if fs.NArg() == 0 {
+			fs.Usage()
+			return fmt.Errorf("no notes given")
+		}
+		var notes []note.Note
+		for _, name := range fs.Args() {
+			n, err := parseRoot(name, opts.accidental)
+			if err != nil {
+				return err
+			}
+			notes = append(notes, n)
+		}
+		if *scales {
+			return printScaleMatches(notes, *limit)
+		}
+		c, err := chord.NewChord(notes[0])
+		if err != nil {
+			return err
+		}
+		matches = c.Identify(notes)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no chord found")
+	}
+	for _, m := range matches {
+		fmt.Println(m)
+	}
+	return nil
+}
+
+// runFit lists the scales that fit over a chord with -chord, or the chords
+// that fit inside a scale with -scale.
+func runFit(args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("fit", flag.ExitOnError)
+	fs.StringVar(&opts.root, "root", "C", "root note of the chord or scale, e.g. C, F#, Bb")
+	fs.StringVar(&opts.accidental, "accidental", "", "spell notes with sharp or flat (default: from -root, flat for naturals)")
+	chordName := fs.String("chord", "", "list the scales that hold every tone of this chord, e.g. Dom7th")
+	fs.StringVar(&opts.scales, "scale", "", "list the chords whose tones are all in this scale, e.g. dorian")
+	addLibraryFlag(fs)
+	fs.Parse(args)
+
+	if (*chordName == "") == (opts.scales == "") {
+		fs.Usage()
+		return fmt.Errorf("give one of -chord or -scale")
+	}
+	root, err := parseRoot(opts.root, opts.accidental)
+	if err != nil {
+		return err
+	}
+
+	if *chordName != "" {
+		c, err := chord.NewChord(root)
+		if err != nil {
+			return err
+		}
+		name, err := findChord(c, *chordName)
+		if err != nil {
+			return err
+		}
+		fits := c.ScalesFor(name)
+		if len(fits) == 0 {
+			return fmt.Errorf("no scale holds %s %s", keyName(root), name)
+		}
+		for _, fit := range fits {
+			var avoid strings.Builder
+			for _, n := range fit.Avoid {
+				avoid.WriteString(n.String())
+			}
+			fmt.Printf("%-22s avoid: %d %s\n", fit.Scale, len(fit.Avoid), avoid.String())
+		}
+		return nil
+	}
+
+	s, err := scale.NewScale(root)
+	if err != nil {
+		return err
+	}
+	scaleNames, err := filterScales(s, opts.scales)
+	if err != nil {
+		return err
+	}
+	for _, scaleName := range scaleNames {
+		fmt.Printf("%s %s\n", keyName(root), scaleName)
+		for _, fit := range chord.ChordsIn(s, scaleName) {
+			c, err := chord.NewChord(fit.Root)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  %d %-14s %s\n", fit.Degree, keyName(fit.Root)+" "+fit.Name, c.GetChordNotes(fit.Name))
+		}
+	}
+	return nil
+}
+
+// printScaleMatches prints up to limit scales that hold notes, exact
+// matches first.
+func printScaleMatches(notes []note.Note, limit int) error {
+	matches := scale.Identify(notes)
+	if len(matches) == 0 {
+		return fmt.Errorf("no scale holds those notes")
+	}
+	for i, m := range matches {
+		if limit > 0 && i == limit {
+			fmt.Printf("... %d more\n", len(matches)-limit)
+			break
+		}
+		extra := "exact"
+		if m.Extra > 0 {
+			extra = fmt.Sprintf("+%d", m.Extra)
+		}
+		fmt.Printf("%-26s %s\n", keyName(m.Root)+" "+m.Scale, extra)
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package diagram
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/llgcode/draw2d"
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/mrgrenier/GuitarScales/chord"
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+const (
+	chordBoxTop    = 300.0
+	chordBoxWidth  = 500.0
+	chordFretSpace = 120.0
+	// chordMinFrets is the fewest frets a chord box shows.
+	chordMinFrets = 4
+)
+
+// ChordDiagram draws one voicing of a chord as a chord box: the strings
+// run down from the nut, or from the fret number when the voicing sits
+// higher up the neck, with open and muted strings marked above the box and
+// the fingers below it.
+type ChordDiagram struct {
+	voicing      chord.Voicing
+	tuning       tuning.Tuning
+	root         note.Note
+	startFret    int
+	numFrets     int
+	radius       float64
+	boxLeft      float64
+	stringSpace  float64
+	canvasWidth  int
+	canvasHeight int
+	dest         *image.RGBA
+	gc           *draw2dimg.GraphicContext
+}
+
+// ChordOption configures a ChordDiagram built by NewChordDiagram.
+type ChordOption func(*ChordDiagram)
+
+// WithChordRoot names the fretted notes by their interval above root, which
+// is C by default.
+func WithChordRoot(root note.Note) ChordOption {
+	return func(c *ChordDiagram) {
+		c.root = root
+	}
+}
+
+// WithChordTuning draws the voicing for an instrument tuned to t instead of
+// a guitar in standard tuning. The voicing has one fret per string of t.
+func WithChordTuning(t tuning.Tuning) ChordOption {
+	return func(c *ChordDiagram) {
+		c.tuning = t
+	}
+}
+
+// NewChordDiagram returns a chord box for voicing v. Strings past the end
+// of v are drawn muted. It fails when the font can't be loaded.
+func NewChordDiagram(v chord.Voicing, opts ...ChordOption) (Diagram, error) {
+	c := &ChordDiagram{voicing: v, tuning: tuning.Standard(), root: note.Note{Name: "C"},
+		startFret: 1, numFrets: chordMinFrets, radius: 38,
+		canvasWidth: 800, canvasHeight: 1000}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Voicings that fit under the nut start there, the rest start on
+	// their lowest fret.
+	low, high := v.FretSpan()
+	if high > chordMinFrets {
+		c.startFret = low
+	}
+	c.numFrets = max(chordMinFrets, high-c.startFret+1)
+
+	c.stringSpace = chordBoxWidth / float64(max(len(c.tuning.Strings)-1, 1))
+	c.radius = min(c.radius, c.stringSpace*0.4)
+	c.boxLeft = (float64(c.canvasWidth) - chordBoxWidth) / 2
+	c.canvasHeight = max(c.canvasHeight, int(chordBoxTop+chordFretSpace*float64(c.numFrets)+200))
+
+	c.dest = image.NewRGBA(image.Rect(0, 0, c.canvasWidth, c.canvasHeight))
+	c.gc = draw2dimg.NewGraphicContext(c.dest)
+
+	if err := setFont(c.gc); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Compile-time check that *ChordDiagram implements Diagram.
+var _ Diagram = (*ChordDiagram)(nil)
+
+// fret returns the fret played on string s, or chord.Muted.
+func (c *ChordDiagram) fret(s int) int {
+	if s < len(c.voicing.Frets) {
+		return c.voicing.Frets[s]
+	}
+	return chord.Muted
+}
+
+func (c *ChordDiagram) stringX(s int) float64 {
+	return c.boxLeft + float64(s)*c.stringSpace
+}
+
+// fretY returns the height of the middle of fret, where its dot goes.
+func (c *ChordDiagram) fretY(fret int) float64 {
+	return chordBoxTop + (float64(fret-c.startFret)+0.5)*chordFretSpace
+}
+
+func (c *ChordDiagram) DrawDiagram() {
+
+	lineColor := color.RGBA{0x44, 0x44, 0x44, 0xff}
+	c.gc.SetStrokeColor(lineColor)
+	c.gc.SetFillColor(lineColor)
+	c.gc.SetLineWidth(2)
+
+	right := c.stringX(len(c.tuning.Strings) - 1)
+	bottom := chordBoxTop + chordFretSpace*float64(c.numFrets)
+
+	// The frets, then the strings
+	for f := 0; f <= c.numFrets; f++ {
+		y := chordBoxTop + float64(f)*chordFretSpace
+		c.gc.BeginPath()
+		c.gc.MoveTo(c.boxLeft, y)
+		c.gc.LineTo(right, y)
+		c.gc.Stroke()
+	}
+	for s := range c.tuning.Strings {
+		c.gc.BeginPath()
+		c.gc.MoveTo(c.stringX(s), chordBoxTop)
+		c.gc.LineTo(c.stringX(s), bottom)
+		c.gc.Stroke()
+	}
+
+	// A thick nut when the box starts at the first fret, otherwise the
+	// number of the first fret beside it
+	fontSize := 36.0
+	c.gc.SetFontSize(fontSize)
+	if c.startFret == 1 {
+		c.gc.SetLineWidth(14)
+		c.gc.BeginPath()
+		c.gc.MoveTo(c.boxLeft-1, chordBoxTop-6)
+		c.gc.LineTo(right+1, chordBoxTop-6)
+		c.gc.Stroke()
+		c.gc.SetLineWidth(2)
+	} else {
+		label := strconv.Itoa(c.startFret) + "fr"
+		left, _, r, _ := c.gc.GetStringBounds(label)
+		c.gc.FillStringAt(label, c.boxLeft-c.radius-16-(r-left), c.fretY(c.startFret)+fontSize/3)
+	}
+
+	// Open strings get a ring and muted strings a cross above the nut
+	markerY := chordBoxTop - 30 - c.radius/2
+	markerR := c.radius / 2
+	c.gc.SetLineWidth(4)
+	for s := range c.tuning.Strings {
+		x := c.stringX(s)
+		switch c.fret(s) {
+		case 0:
+			c.gc.BeginPath()
+			c.gc.MoveTo(x+markerR, markerY)
+			c.gc.ArcTo(x, markerY, markerR, markerR, 0, -math.Pi*2)
+			c.gc.Stroke()
+		case chord.Muted:
+			c.gc.BeginPath()
+			c.gc.MoveTo(x-markerR, markerY-markerR)
+			c.gc.LineTo(x+markerR, markerY+markerR)
+			c.gc.MoveTo(x+markerR, markerY-markerR)
+			c.gc.LineTo(x-markerR, markerY+markerR)
+			c.gc.Stroke()
+		}
+	}
+	c.gc.SetLineWidth(2)
+
+	// The fingers under the strings they hold
+	for s := range c.tuning.Strings {
+		finger := c.voicing.Finger(s)
+		if finger == 0 || c.fret(s) <= 0 {
+			continue
+		}
+		label := strconv.Itoa(finger)
+		left, _, r, _ := c.gc.GetStringBounds(label)
+		c.gc.FillStringAt(label, c.stringX(s)-(r-left)/2, bottom+fontSize*1.5)
+	}
+
+}
+
+// ColorScale draws a dot on every fretted note of the voicing, labelled
+// with its interval above the root and colored like the scale diagrams:
+// red for the root, black for the intervals given and grey for any other
+// note.
+func (c *ChordDiagram) ColorScale(interval []string) {
+
+	intervalmap := scaleIntervals(interval)
+
+	var radius = c.radius
+
+	blankNoteColor := color.RGBA{0xee, 0xee, 0xee, 0xff}
+	blankNoteFontColor := color.RGBA{0x00, 0x00, 0x00, 0xff}
+	rootNoteColor := color.RGBA{0xff, 0x44, 0x44, 0xff}
+	rootNoteFontColor := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	scaleNoteColor := color.RGBA{0x00, 0x00, 0x00, 0xff}
+	scaleNoteFontColor := color.RGBA{0xff, 0xff, 0xff, 0xff}
+
+	c.drawBarre(scaleNoteColor)
+
+	names := intervalNames()
+	for s, open := range c.tuning.Strings {
+		fret := c.fret(s)
+		if fret <= 0 {
+			continue
+		}
+
+		noteColor := blankNoteColor
+		fontColor := blankNoteFontColor
+		var note string
+		for _, note = range names[(open.PitchClass()+fret-c.root.PitchClass()+12)%12] {
+			if note == "1" {
+				noteColor = rootNoteColor
+				fontColor = rootNoteFontColor
+				note = "R"
+				break
+			} else if intervalmap[note] != "" {
+				noteColor = scaleNoteColor
+				fontColor = scaleNoteFontColor
+				note = intervalmap[note]
+				break
+			}
+		}
+		if noteColor == blankNoteColor {
+			note = flatName(note)
+		}
+
+		x, y := c.stringX(s), c.fretY(fret)
+		c.gc.BeginPath() // Initialize a new path
+		c.gc.SetFillColor(noteColor)
+		c.gc.SetStrokeColor(noteColor)
+		c.gc.MoveTo(x+radius, y)
+		c.gc.ArcTo(x, y, radius, radius, 0, -math.Pi*2)
+		c.gc.FillStroke()
+		c.DrawInterval(note, x, y, radius, fontColor)
+	}
+
+}
+
+// barre returns the strings and fret of the first finger barre, or false
+// when the first finger does not hold more than one string on one fret.
+func (c *ChordDiagram) barre() (first, last, barreFret int, ok bool) {
+	first, last = -1, -1
+	for s := range c.tuning.Strings {
+		if c.voicing.Finger(s) != 1 || c.fret(s) <= 0 {
+			continue
+		}
+		if first < 0 {
+			first, barreFret = s, c.fret(s)
+		} else if c.fret(s) != barreFret {
+			return 0, 0, 0, false
+		}
+		last = s
+	}
+	if first < 0 || first == last {
+		return 0, 0, 0, false
+	}
+	return first, last, barreFret, true
+}
+
+// drawBarre draws a bar under the dots when the first finger holds more
+// than one string on the same fret.
+func (c *ChordDiagram) drawBarre(barreColor color.RGBA) {
+	first, last, barreFret, ok := c.barre()
+	if !ok {
+		return
+	}
+
+	y := c.fretY(barreFret)
+	c.gc.SetStrokeColor(barreColor)
+	c.gc.SetLineWidth(c.radius * 0.8)
+	c.gc.SetLineCap(draw2d.RoundCap)
+	c.gc.BeginPath()
+	c.gc.MoveTo(c.stringX(first), y)
+	c.gc.LineTo(c.stringX(last), y)
+	c.gc.Stroke()
+	c.gc.SetLineCap(draw2d.ButtCap)
+	c.gc.SetLineWidth(2)
+}
+
+func (c *ChordDiagram) DrawInterval(note string, x, y, radius float64, textColor color.RGBA) {
+
+	var noteFontSize = 34 * radius / 40
+	var accidentalsFontSize = 20 * radius / 40
+
+	c.gc.SetFillColor(textColor)
+	c.gc.SetStrokeColor(textColor)
+
+	accidentals, note := splitAccidentals(note)
+	if accidentals != "" {
+		width := accidentalsFontSize * float64(utf8.RuneCountInString(accidentals))
+		c.gc.SetFontSize(accidentalsFontSize)
+		c.gc.FillStringAt(accidentals, x-(radius+width)/2.25, y+accidentalsFontSize/2)
+		x = x + width/2
+	}
+
+	c.gc.SetFontSize(noteFontSize)
+	c.gc.FillStringAt(note, x-(radius+noteFontSize)/4.75, y+(radius+noteFontSize)/4.5)
+
+}
+
+func (c *ChordDiagram) DrawTitle(chordName, chordNotes string, x, y float64) {
+
+	textColor := color.RGBA{0x00, 0x00, 0x00, 0xff}
+
+	var fontSize float64 = 44
+	var notesFontSize = fontSize * .75
+
+	c.gc.SetFillColor(textColor)
+	c.gc.SetStrokeColor(textColor)
+
+	c.gc.SetFontSize(fontSize)
+	c.gc.FillStringAt(chordName, x, y)
+
+	c.gc.SetFontSize(notesFontSize)
+	c.gc.FillStringAt(chordNotes, x, y+fontSize+fontSize/2)
+
+}
+
+func (c *ChordDiagram) SaveScaleDiagram(filename string) {
+	err := draw2dimg.SaveToPngFile(filename, c.dest)
+	if err != nil {
+		return
+	}
+}
+
+func (c *ChordDiagram) TilePNGsToPDF(inputDir, outPDFPath string) error {
+	return tileSectionsToPDF([]PDFSection{{Dir: inputDir}}, outPDFPath, gridLayout)
+}
+
+// TileSectionsToPDF writes each section's chord boxes to the PDF, nine to
+// a page, starting every section on a new page under its title.
+func (c *ChordDiagram) TileSectionsToPDF(sections []PDFSection, outPDFPath string) error {
+	return tileSectionsToPDF(sections, outPDFPath, gridLayout)
+}
@@ -0,0 +1,28 @@
+package diagram
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mrgrenier/GuitarScales/chord"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+func TestBarreOfParsedVoicing(t *testing.T) {
+	v, err := chord.ParseVoicing("133211")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if barre, ok := v.Fingering(); !barre || !ok {
+		t.Fatalf("Fingering() = %v, %v, want a barre", barre, ok)
+	}
+	if want := []int{1, 3, 4, 2, 1, 1}; !slices.Equal(v.Fingers, want) {
+		t.Errorf("Fingers = %v, want %v", v.Fingers, want)
+	}
+
+	c := &ChordDiagram{voicing: v, tuning: tuning.Standard()}
+	first, last, fret, ok := c.barre()
+	if !ok || first != 0 || last != 5 || fret != 1 {
+		t.Errorf("barre() = %d, %d, %d, %v, want strings 0 to 5 on fret 1", first, last, fret, ok)
+	}
+}
@@ -8,4 +8,5 @@ type Diagram interface {
 	DrawTitle(scaleName, scaleNotes string, x, y float64)
 	SaveScaleDiagram(filename string)
 	TilePNGsToPDF(inputDir, outPDFPath string) error
+	TileSectionsToPDF(sections []PDFSection, outPDFPath string) error
 }
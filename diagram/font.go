@@ -0,0 +1,46 @@
+package diagram
+
+import (
+	"os"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/llgcode/draw2d"
+)
+
+// fontFile is the font every diagram labels its notes and titles with.
+const fontFile = "./resource/font/wqy-zenhei.ttf"
+
+// setFont loads the diagram font and makes it gc's font.
+func setFont(gc draw2d.GraphicContext) error {
+	draw2d.SetFontFolder("./resource/font")
+	b, err := os.ReadFile(fontFile)
+	if err != nil {
+		return err
+	}
+	font, err := truetype.Parse(b)
+	if err != nil {
+		return err
+	}
+	fontdata := draw2d.FontData{Name: "wgy-zenhei", Family: draw2d.FontFamilyMono, Style: draw2d.FontStyleNormal}
+	draw2d.RegisterFont(fontdata, font)
+	gc.SetFontData(fontdata)
+	return nil
+}
+
+// flatNames are the flat names the notes outside a scale are labelled with
+// in place of the sharp ones.
+var flatNames = map[string]string{
+	"#2": "b3",
+	"#4": "b5",
+	"#5": "b6",
+	"#6": "b7",
+}
+
+// flatName returns the flat name of interval, or interval itself when it
+// has none.
+func flatName(interval string) string {
+	if name, ok := flatNames[interval]; ok {
+		return name
+	}
+	return interval
+}
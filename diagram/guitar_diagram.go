@@ -1,178 +1,326 @@
 package diagram
 
 import (
-	"fmt"
 	"image"
 	"image/color"
-	"image/png"
-	"log"
 	"math"
-	"os"
-	"path/filepath"
-	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
-	"github.com/golang/freetype/truetype"
-	"github.com/llgcode/draw2d"
 	"github.com/llgcode/draw2d/draw2dimg"
-	"github.com/llgcode/draw2d/draw2dpdf"
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+// MaxFrets is the highest fret a FretBoard can show.
+const MaxFrets = 24
+
+const (
+	// minFretWidth is the narrowest a fret is drawn, in pixels, so the
+	// note circles do not overlap.
+	minFretWidth = 90
+	// boardOffsetX is the left margin of the fretboard, in pixels.
+	boardOffsetX = 40.0
+	// boardOffsetY is the centre line of the strings, in pixels.
+	boardOffsetY = 564.0
+	// neckTop is the highest string of a full neck map, in pixels.
+	neckTop = 200.0
+	// neckStringSpacing is the distance between strings of a full neck
+	// map, in pixels.
+	neckStringSpacing = 48.0
+	// guitarScaleLength is the nut to bridge distance of a guitar, in inches.
+	guitarScaleLength = 25.5
 )
 
 type FretBoard struct {
 	scaleLength         float64
+	startFret           int
 	numFrets            int
+	windowSet           bool
+	tuning              tuning.Tuning
+	root                *note.Note
+	pattern             map[int]map[int]bool
+	fullNeck            bool
+	radius              float64
+	openColumn          float64
+	boardTop            float64
+	boardBottom         float64
+	pxPerInch           float64
 	canvasWidth         int
 	canvasHeight        int
 	strings             int
 	noteposX            []float64
 	noteposY            []float64
 	StringFret2Interval map[int]map[int]map[string]bool
-	flatName            map[string]string
 	dest                *image.RGBA
 	gc                  *draw2dimg.GraphicContext
 }
 
+// FretBoardOption configures a FretBoard built by NewFretBoard.
+type FretBoardOption func(*FretBoard)
+
+// WithFretWindow shows numFrets frets starting at startFret instead of the
+// first six. The window is kept within the first 24 frets.
+func WithFretWindow(startFret, numFrets int) FretBoardOption {
+	return func(fb *FretBoard) {
+		fb.startFret = min(max(startFret, 1), MaxFrets)
+		fb.numFrets = min(max(numFrets, 1), MaxFrets-fb.startFret+1)
+		fb.windowSet = true
+	}
+}
+
+// WithFullNeck maps the whole neck from the open strings up to lastFret,
+// at most 24, with smaller notes and the inlay dots.
+func WithFullNeck(lastFret int) FretBoardOption {
+	return func(fb *FretBoard) {
+		fb.fullNeck = true
+		fb.startFret = 0
+		fb.numFrets = min(max(lastFret, 1), MaxFrets) + 1
+		fb.windowSet = true
+	}
+}
+
+// WithRoot lays the intervals out for the key of root. Unless a window is
+// given, the first six frets shown are the ones that put the root on the
+// second of them on the lowest string.
+func WithRoot(root note.Note) FretBoardOption {
+	return func(fb *FretBoard) {
+		fb.root = &root
+	}
+}
+
+// WithPattern colors only the scale notes at the given frets, listed per
+// string from the lowest, such as one position of a scale. The other scale
+// notes are drawn like notes outside the scale.
+func WithPattern(frets [][]int) FretBoardOption {
+	return func(fb *FretBoard) {
+		fb.pattern = make(map[int]map[int]bool)
+		for s, stringFrets := range frets {
+			fb.pattern[s] = make(map[int]bool)
+			for _, fret := range stringFrets {
+				fb.pattern[s][fret] = true
+			}
+		}
+	}
+}
+
+// WithInstrument draws the strings and frets of a fretted instrument preset
+// in place of a standard tuned guitar.
+func WithInstrument(inst tuning.Instrument) FretBoardOption {
+	return func(fb *FretBoard) {
+		fb.scaleLength = inst.ScaleLength
+		fb.tuning = inst.Tuning
+		fb.strings = len(inst.Tuning.Strings)
+	}
+}
+
+// WithTuning sets the open strings in place of standard tuning.
+func WithTuning(t tuning.Tuning) FretBoardOption {
+	return func(fb *FretBoard) {
+		fb.tuning = t
+		fb.strings = len(t.Strings)
+	}
+}
+
 // NewGuitarDiagram returns the common Diagram interface backed by the guitar fretboard implementation.
-func NewGuitarDiagram() Diagram {
-	return NewFretBoard()
+func NewGuitarDiagram(opts ...FretBoardOption) (Diagram, error) {
+	fb, err := NewFretBoard(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return fb, nil
 }
 
 // Compile-time check that *FretBoard implements Diagram.
 var _ Diagram = (*FretBoard)(nil)
 
-func NewFretBoard() *FretBoard {
-	fb := &FretBoard{scaleLength: 25.5, numFrets: 6,
-		canvasWidth: 1188, canvasHeight: 940, strings: 6}
+func NewFretBoard(opts ...FretBoardOption) (*FretBoard, error) {
+	fb := &FretBoard{scaleLength: guitarScaleLength, startFret: 1, numFrets: 6,
+		pxPerInch: 150, canvasWidth: 1188, canvasHeight: 1000,
+		tuning: tuning.Standard(), strings: 6, radius: 40,
+		boardTop: boardOffsetY - 2.2*150, boardBottom: boardOffsetY + 2.2*150}
+	for _, opt := range opts {
+		opt(fb)
+	}
+	if err := fb.tuning.Validate(); err != nil {
+		return nil, err
+	}
 
-	fb.dest = image.NewRGBA(image.Rect(0, 0, fb.canvasWidth, fb.canvasHeight))
-	fb.gc = draw2dimg.NewGraphicContext(fb.dest)
+	// Without a root the layout is relative, with the root on the second
+	// fret of the lowest string.
+	rootPitch := (fb.tuning.Strings[0].PitchClass() + 2) % 12
+	if fb.root != nil {
+		rootPitch = fb.root.PitchClass()
+		if !fb.windowSet {
+			fb.startFret = (rootPitch-fb.tuning.Strings[0].PitchClass()+12)%12 - 1
+			if fb.startFret < 1 {
+				fb.startFret += 12
+			}
+		}
+	}
 
-	draw2d.SetFontFolder("./resource/font")
-	b, err := os.ReadFile("./resource/font/wqy-zenhei.ttf")
-	if err != nil {
-		log.Fatal(err)
+	// A full neck map is as wide as it needs to be with small notes.
+	fretWidth := float64(minFretWidth)
+	if fb.fullNeck {
+		fb.radius = 18
+		fretWidth = 2*fb.radius + 8
+		fb.openColumn = 2*fb.radius + 24
+		fb.boardTop = neckTop
+		fb.boardBottom = neckTop + neckStringSpacing*float64(fb.strings-1)
+		fb.canvasHeight = int(fb.boardBottom + 4*fb.radius)
 	}
-	font, err := truetype.Parse(b)
-	if err != nil {
-		log.Fatal(err)
+
+	// Every window is drawn as wide as the first six frets of a guitar,
+	// unless that squeezes the frets too narrow for the note circles.
+	distances := fretDistances(fb.scaleLength)
+	firstFret := max(fb.startFret, 1)
+	lastFret := fb.startFret + fb.numFrets - 1
+	window := distances[lastFret] - distances[firstFret-1]
+	narrowest := distances[lastFret] - distances[lastFret-1]
+	if fb.fullNeck {
+		fb.pxPerInch = fretWidth / narrowest
+	} else {
+		fb.pxPerInch = fb.pxPerInch * fretDistances(guitarScaleLength)[6] / window
+	}
+	if narrowest*fb.pxPerInch < fretWidth {
+		fb.pxPerInch = fretWidth / narrowest
 	}
-	fontdata := draw2d.FontData{Name: "wgy-zenhei", Family: draw2d.FontFamilyMono, Style: draw2d.FontStyleNormal}
-	draw2d.RegisterFont(
-		fontdata,
-		font,
-	)
+	fb.canvasWidth = max(fb.canvasWidth, int(math.Ceil(1.5*boardOffsetX+fb.openColumn+window*fb.pxPerInch)))
 
-	fb.gc.SetFontData(fontdata)
+	fb.dest = image.NewRGBA(image.Rect(0, 0, fb.canvasWidth, fb.canvasHeight))
+	fb.gc = draw2dimg.NewGraphicContext(fb.dest)
+
+	if err := setFont(fb.gc); err != nil {
+		return nil, err
+	}
 
 	fb.StringFret2Interval = make(map[int]map[int]map[string]bool)
 	for f := 0; f < fb.numFrets; f++ {
 		fb.StringFret2Interval[f] = make(map[int]map[string]bool)
-		for s := 0; s < 6; s++ {
+		for s := 0; s < fb.strings; s++ {
 			fb.StringFret2Interval[f][s] = make(map[string]bool)
 		}
 	}
 
-	fb.StringFret2Interval[0][0]["7"] = true
-	fb.StringFret2Interval[1][0]["1"] = true
-	fb.StringFret2Interval[2][0]["b2"] = true
-	fb.StringFret2Interval[3][0]["2"] = true
-	fb.StringFret2Interval[4][0]["#2"] = true
-	fb.StringFret2Interval[4][0]["b3"] = true
-	fb.StringFret2Interval[5][0]["3"] = true
-
-	fb.StringFret2Interval[0][1]["3"] = true
-	fb.StringFret2Interval[1][1]["4"] = true
-	fb.StringFret2Interval[2][1]["#4"] = true
-	fb.StringFret2Interval[2][1]["b5"] = true
-	fb.StringFret2Interval[3][1]["5"] = true
-	fb.StringFret2Interval[4][1]["#5"] = true
-	fb.StringFret2Interval[4][1]["b6"] = true
-	fb.StringFret2Interval[5][1]["6"] = true
-
-	fb.StringFret2Interval[0][2]["6"] = true
-	fb.StringFret2Interval[1][2]["#6"] = true
-	fb.StringFret2Interval[1][2]["b7"] = true
-	fb.StringFret2Interval[2][2]["7"] = true
-	fb.StringFret2Interval[3][2]["1"] = true
-	fb.StringFret2Interval[4][2]["b2"] = true
-	fb.StringFret2Interval[5][2]["2"] = true
-
-	fb.StringFret2Interval[0][3]["2"] = true
-	fb.StringFret2Interval[1][3]["#2"] = true
-	fb.StringFret2Interval[1][3]["b3"] = true
-	fb.StringFret2Interval[2][3]["3"] = true
-	fb.StringFret2Interval[3][3]["4"] = true
-	fb.StringFret2Interval[4][3]["#4"] = true
-	fb.StringFret2Interval[4][3]["b5"] = true
-	fb.StringFret2Interval[5][3]["5"] = true
-
-	fb.StringFret2Interval[0][4]["#4"] = true
-	fb.StringFret2Interval[0][4]["b5"] = true
-	fb.StringFret2Interval[1][4]["5"] = true
-	fb.StringFret2Interval[2][4]["#5"] = true
-	fb.StringFret2Interval[2][4]["b6"] = true
-	fb.StringFret2Interval[3][4]["6"] = true
-	fb.StringFret2Interval[4][4]["#6"] = true
-	fb.StringFret2Interval[4][4]["b7"] = true
-	fb.StringFret2Interval[5][4]["7"] = true
-
-	fb.StringFret2Interval[0][5]["7"] = true
-	fb.StringFret2Interval[1][5]["1"] = true
-	fb.StringFret2Interval[2][5]["b2"] = true
-	fb.StringFret2Interval[3][5]["2"] = true
-	fb.StringFret2Interval[4][5]["#2"] = true
-	fb.StringFret2Interval[4][5]["b3"] = true
-	fb.StringFret2Interval[5][5]["3"] = true
-
-	fb.flatName = make(map[string]string)
-	fb.flatName["#2"] = "b3"
-	fb.flatName["#4"] = "b5"
-	fb.flatName["#5"] = "b6"
-	fb.flatName["#6"] = "b7"
-
-	return fb
+	// Each position gets the names of its interval above the root.
+	names := intervalNames()
+	for f := 0; f < fb.numFrets; f++ {
+		fret := fb.startFret + f
+		for s, open := range fb.tuning.Strings {
+			offset := (open.PitchClass() + fret - rootPitch + 12) % 12
+			for _, name := range names[offset] {
+				fb.StringFret2Interval[f][s][name] = true
+			}
+		}
+	}
+
+	return fb, nil
+}
+
+// intervalNames maps each semitone offset from the root to the interval
+// names that land on it, e.g. 3 to "#2" and "b3".
+func intervalNames() map[int][]string {
+	names := make(map[int][]string)
+	for name, offset := range scale.NewInterval().GetOffset() {
+		names[offset] = append(names[offset], name)
+	}
+	return names
+}
+
+// scaleIntervals indexes intervals by every name intervalNames gives the
+// note they land on, so compound and doubly altered intervals such as "9"
+// or "bb7" are found too. Each name maps to the interval as given, which
+// the note is labelled with.
+func scaleIntervals(intervals []string) map[string]string {
+	names := intervalNames()
+	inter := scale.NewInterval()
+	m := make(map[string]string)
+	for _, intr := range intervals {
+		offset, err := inter.IntervalToOffset(intr)
+		if err != nil {
+			continue
+		}
+		for _, name := range names[offset] {
+			if _, ok := m[name]; !ok || name == intr {
+				m[name] = intr
+			}
+		}
+	}
+	return m
+}
+
+// splitAccidentals splits the sharps and flats off the front of an
+// interval, returned as the signs they are drawn with.
+func splitAccidentals(interval string) (accidentals, degree string) {
+	degree = strings.TrimLeft(interval, "b#")
+	for _, r := range interval[:len(interval)-len(degree)] {
+		if r == 'b' {
+			accidentals += string('\u266D')
+		} else {
+			accidentals += string('\u266F')
+		}
+	}
+	return accidentals, degree
+}
+
+// fretDistances returns the distance in inches from the nut to every fret
+// up to MaxFrets for a scale length, index 0 being the nut itself.
+func fretDistances(scaleLength float64) []float64 {
+	distances := make([]float64, MaxFrets+1)
+	for fret := 1; fret <= MaxFrets; fret++ {
+		bridgeToFret := scaleLength - distances[fret-1]
+		distances[fret] = distances[fret-1] + bridgeToFret/17.817
+	}
+	return distances
 }
 
 func (fb *FretBoard) DrawDiagram() {
-	var offsetX float64 = 40
-	var offsetY = (float64(fb.canvasHeight) / 2) + float64(fb.canvasHeight)/10
-	var scale float64 = 150
-	var scale_factor = 0.0
-	var distanceFromNut = 0.0
-	var bridgeToFret = 0.0
-	var nut = []float64{(distanceFromNut * scale) + offsetX, (2.2 * scale) + offsetY, (distanceFromNut * scale) + offsetX, (-2.2 * scale) + offsetY}
-	var va = []float64{(distanceFromNut * scale) + offsetX, (2.2 * scale) + offsetY, (distanceFromNut * scale) + offsetX, (-2.2 * scale) + offsetY}
+	var offsetX = boardOffsetX
+	var distances = fretDistances(fb.scaleLength)
+	var firstFret = max(fb.startFret, 1)
+	var distanceFromNut = distances[firstFret-1]
 
 	// Initialize the graphic context on an RGBA image
 	fb.gc.SetStrokeColor(color.RGBA{0x44, 0x44, 0x44, 0xff})
 	fb.gc.SetLineWidth(2)
 
-	// Draw the nut
+	// Open strings sit in a column of their own before the nut
+	if fb.startFret == 0 {
+		fb.noteposX = append(fb.noteposX, offsetX+fb.openColumn/2)
+		offsetX += fb.openColumn
+		fb.gc.SetLineWidth(6)
+	}
+
+	var nut = []float64{offsetX, fb.boardBottom, offsetX, fb.boardTop}
+	var va = nut
+
+	// Draw the nut, or the fret below the window when it starts higher up
 	fb.gc.BeginPath() // Initialize a new path
 	fb.gc.MoveTo(nut[0], nut[1])
 	fb.gc.LineTo(nut[2], nut[3])
 	fb.gc.FillStroke()
+	fb.gc.SetLineWidth(2)
 
 	// Draw the frets
-	for fret := 1; fret <= fb.numFrets; fret++ {
-		bridgeToFret = fb.scaleLength - distanceFromNut
-		scale_factor = bridgeToFret / 17.817
-		prevDistanceFromNut := distanceFromNut
-		distanceFromNut = distanceFromNut + scale_factor
-
-		fretPos := (distanceFromNut * scale) + offsetX
-		prevFret := (prevDistanceFromNut * scale) + offsetX
+	for fret := firstFret; fret < fb.startFret+fb.numFrets; fret++ {
+		fretPos := ((distances[fret] - distanceFromNut) * fb.pxPerInch) + offsetX
+		prevFret := ((distances[fret-1] - distanceFromNut) * fb.pxPerInch) + offsetX
 
 		fb.noteposX = append(fb.noteposX, ((prevFret + fretPos) / 2))
-		va = []float64{fretPos, (2.2 * scale) + offsetY, fretPos, (-2.2 * scale) + offsetY}
+		va = []float64{fretPos, fb.boardBottom, fretPos, fb.boardTop}
 		fb.gc.BeginPath() // Initialize a new path
 		fb.gc.MoveTo(va[0], va[1])
 		fb.gc.LineTo(va[2], va[3])
 		fb.gc.FillStroke()
 	}
 
+	if fb.fullNeck {
+		fb.drawInlays()
+	}
+
 	segments := (nut[1] - nut[3]) / (float64(fb.strings) - 1)
 
 	// draw the strings
@@ -184,17 +332,56 @@ func (fb *FretBoard) DrawDiagram() {
 		fb.gc.FillStroke()
 	}
 
+	fb.drawFretNumbers(nut[1] + 2*fb.radius)
 }
 
-func (fb *FretBoard) ColorScale(interval []string) {
+// drawInlays draws the position dots of the neck, doubled at the octaves.
+func (fb *FretBoard) drawInlays() {
+	radius := fb.radius / 2
+	middle := (fb.boardTop + fb.boardBottom) / 2
+	spacing := (fb.boardBottom - fb.boardTop) / float64(fb.strings-1)
+
+	fb.gc.SetFillColor(color.RGBA{0xcc, 0xcc, 0xcc, 0xff})
+	fb.gc.SetStrokeColor(color.RGBA{0xcc, 0xcc, 0xcc, 0xff})
+	for f, x := range fb.noteposX {
+		var ys []float64
+		switch (fb.startFret + f) % 12 {
+		case 3, 5, 7, 9:
+			ys = []float64{middle}
+		case 0:
+			if fb.startFret+f > 0 {
+				ys = []float64{middle - spacing, middle + spacing}
+			}
+		}
+		for _, y := range ys {
+			fb.gc.BeginPath()
+			fb.gc.MoveTo(x+radius, y)
+			fb.gc.ArcTo(x, y, radius, radius, 0, -math.Pi*2)
+			fb.gc.FillStroke()
+		}
+	}
+}
 
-	intervalmap := make(map[string]bool)
-	for _, i := range interval {
-		intervalmap[i] = true
+// drawFretNumbers labels every fret of the window below the board, centred
+// under its notes.
+func (fb *FretBoard) drawFretNumbers(y float64) {
+	var fontSize = max(30*fb.radius/40, 18)
+
+	fb.gc.SetFillColor(color.RGBA{0x44, 0x44, 0x44, 0xff})
+	fb.gc.SetFontSize(fontSize)
+	for f, x := range fb.noteposX {
+		label := strconv.Itoa(fb.startFret + f)
+		left, _, right, _ := fb.gc.GetStringBounds(label)
+		fb.gc.FillStringAt(label, x-(right-left)/2, y)
 	}
+}
+
+func (fb *FretBoard) ColorScale(interval []string) {
+
+	intervalmap := scaleIntervals(interval)
 
 	// Draw the note circles
-	var radius float64 = 40
+	var radius = fb.radius
 
 	blankNoteColor := color.RGBA{0xee, 0xee, 0xee, 0xff}
 	blankNoteFontColor := color.RGBA{0x00, 0x00, 0x00, 0xff}
@@ -211,23 +398,34 @@ func (fb *FretBoard) ColorScale(interval []string) {
 		for s, y := range fb.noteposY {
 			noteColor = blankNoteColor
 			fontColor = blankNoteFontColor
+			inPattern := fb.inPattern(fb.startFret+f, s)
 			for note = range fb.StringFret2Interval[f][s] {
 				if note == "1" {
-					noteColor = rootNoteColor
-					fontColor = rootNoteFontColor
+					if inPattern {
+						noteColor = rootNoteColor
+						fontColor = rootNoteFontColor
+					}
 					note = "R"
 					break
-				} else if intervalmap[note] == true {
-					noteColor = scaleNoteColor
-					fontColor = scaleNoteFontColor
+				} else if intervalmap[note] != "" {
+					if inPattern {
+						noteColor = scaleNoteColor
+						fontColor = scaleNoteFontColor
+					}
+					note = intervalmap[note]
 					break
 				} else {
 					// for the notes not in the interval favor the flat name ins stead of the sharp name
-					note = fb.getFlatName(note)
+					note = flatName(note)
 				}
 
 			}
 
+			// The full neck map only shows the scale
+			if fb.fullNeck && noteColor == blankNoteColor {
+				continue
+			}
+
 			fb.gc.BeginPath() // Initialize a new path
 			fb.gc.SetFillColor(noteColor)
 			fb.gc.SetStrokeColor(noteColor)
@@ -240,26 +438,29 @@ func (fb *FretBoard) ColorScale(interval []string) {
 
 }
 
+// inPattern reports whether the note on string s at fret is played. Every
+// note is when no pattern was given.
+func (fb *FretBoard) inPattern(fret, s int) bool {
+	if fb.pattern == nil {
+		return true
+	}
+	return fb.pattern[s][fret]
+}
+
 func (fb *FretBoard) DrawInterval(note string, x, y, radius float64, textColor color.RGBA) {
 
-	flat := string([]rune{'\u266D'})
-	sharp := string([]rune{'\u266F'})
-	var noteFontSize float64 = 34
-	var accidentalsFontSize float64 = 20
+	var noteFontSize = 34 * radius / 40
+	var accidentalsFontSize = 20 * radius / 40
 
 	fb.gc.SetFillColor(textColor)
 	fb.gc.SetStrokeColor(textColor)
 
-	if strings.HasPrefix(note, "b") {
+	accidentals, note := splitAccidentals(note)
+	if accidentals != "" {
+		width := accidentalsFontSize * float64(utf8.RuneCountInString(accidentals))
 		fb.gc.SetFontSize(accidentalsFontSize)
-		fb.gc.FillStringAt(flat, x-(radius+accidentalsFontSize)/2.25, y+accidentalsFontSize/2)
-		x = x + accidentalsFontSize/2
-		note = note[1:]
-	} else if strings.HasPrefix(note, "#") {
-		fb.gc.SetFontSize(accidentalsFontSize)
-		fb.gc.FillStringAt(sharp, x-(radius+accidentalsFontSize)/2.25, y+accidentalsFontSize/2)
-		x = x + accidentalsFontSize/2
-		note = note[1:]
+		fb.gc.FillStringAt(accidentals, x-(radius+width)/2.25, y+accidentalsFontSize/2)
+		x = x + width/2
 	}
 
 	fb.gc.SetFontSize(noteFontSize)
@@ -284,6 +485,11 @@ func (fb *FretBoard) DrawTitle(scaleName, scaleNotes string, x, y float64) {
 	fb.gc.SetFontSize(notesFontSize)
 	fb.gc.FillStringAt(scaleNotes, x, y+fontSize+fontSize/2)
 
+	// The tuning goes on the right so the diagram matches the instrument
+	tuningName := "Tuning: " + titleCaseASCIIWords(fb.tuning.String())
+	left, _, right, _ := fb.gc.GetStringBounds(tuningName)
+	fb.gc.FillStringAt(tuningName, float64(fb.canvasWidth)-x-(right-left), y+fontSize+fontSize/2)
+
 }
 
 func (fb *FretBoard) SaveScaleDiagram(filename string) {
@@ -293,104 +499,14 @@ func (fb *FretBoard) SaveScaleDiagram(filename string) {
 	}
 }
 
-func (fb *FretBoard) getFlatName(interval string) string {
-	if flatname, ok := fb.flatName[interval]; ok {
-		return (flatname)
-	}
-	return interval
-}
-
 func (fb *FretBoard) TilePNGsToPDF(inputDir, outPDFPath string) error {
-	entries, err := os.ReadDir(inputDir)
-	if err != nil {
-		return fmt.Errorf("read dir %q: %w", inputDir, err)
-	}
-
-	var pngPaths []string
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		if strings.HasSuffix(strings.ToLower(name), ".png") {
-			pngPaths = append(pngPaths, filepath.Join(inputDir, name))
-		}
-	}
-	sort.Strings(pngPaths)
-	if len(pngPaths) == 0 {
-		return fmt.Errorf("no PNG files found in %q", inputDir)
-	}
-
-	// Letter size in points (1 inch = 72 points)
-	const pageW, pageH = 612.0, 792.0
-
-	// Layout settings (points)
-	const (
-		cols   = 3
-		rows   = 3
-		margin = 36.0 // 0.5"
-		gap    = 12.0
-	)
-
-	cellW := (pageW - 2*margin - float64(cols-1)*gap) / float64(cols)
-	cellH := (pageH - 2*margin - float64(rows-1)*gap) / float64(rows)
-
-	pdf := draw2dpdf.NewPdf("P", "pt", "Letter")
-	gc := draw2dpdf.NewGraphicContext(pdf)
-
-	for i, p := range pngPaths {
-		if i%(cols*rows) == 0 {
-			pdf.AddPage()
-		}
-
-		f, err := os.Open(p)
-		if err != nil {
-			return fmt.Errorf("open %q: %w", p, err)
-		}
-		img, err := png.Decode(f)
-		_ = f.Close()
-		if err != nil {
-			return fmt.Errorf("decode png %q: %w", p, err)
-		}
-
-		idxOnPage := i % (cols * rows)
-		r := idxOnPage / cols
-		c := idxOnPage % cols
-
-		x0 := margin + float64(c)*(cellW+gap)
-		y0 := margin + float64(r)*(cellH+gap)
-
-		iw := float64(img.Bounds().Dx())
-		ih := float64(img.Bounds().Dy())
-
-		// Scale-to-fit (preserve aspect ratio)
-		sx := cellW / iw
-		sy := cellH / ih
-		s := sx
-		if sy < sx {
-			s = sy
-		}
-		drawW := iw * s
-		drawH := ih * s
-
-		// Center in the cell
-		x := x0 + (cellW-drawW)/2
-		y := y0 + (cellH-drawH)/2
-
-		gc.Save()
-		gc.Translate(x, y)
-		gc.Scale(s, s)
-		gc.DrawImage(img)
-		gc.Restore()
-	}
+	return tileSectionsToPDF([]PDFSection{{Dir: inputDir}}, outPDFPath, gridLayout)
+}
 
-	if err := os.MkdirAll(filepath.Dir(outPDFPath), 0o755); err != nil {
-		return fmt.Errorf("mkdir %q: %w", filepath.Dir(outPDFPath), err)
-	}
-	if err := draw2dpdf.SaveToPdfFile(outPDFPath, pdf); err != nil {
-		return fmt.Errorf("save pdf %q: %w", outPDFPath, err)
-	}
-	return nil
+// TileSectionsToPDF writes each section's PNGs to the PDF, starting every
+// section on a new page under its title.
+func (fb *FretBoard) TileSectionsToPDF(sections []PDFSection, outPDFPath string) error {
+	return tileSectionsToPDF(sections, outPDFPath, gridLayout)
 }
 
 // letter of each whitespace-separated word.
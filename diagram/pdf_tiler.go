@@ -8,15 +8,47 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/jung-kurt/gofpdf"
 	"github.com/llgcode/draw2d/draw2dpdf"
 )
 
+// PDFSection is a directory of PNGs that starts on a new page, headed and
+// bookmarked with Title when it is not empty.
+type PDFSection struct {
+	Title string
+	Dir   string
+	// Files, when set, are the PNGs of the section in place of every PNG
+	// in Dir.
+	Files []string
+}
+
+// pdfLayout places the tiles on a Letter page. All values are in points.
+type pdfLayout struct {
+	cols        int
+	rows        int
+	topMargin   float64
+	sideMargin  float64
+	extraOffset float64
+	gap         float64
+	// targetWidth scales every image to this width instead of fitting it
+	// to its cell when it is set.
+	targetWidth float64
+}
+
+// gridLayout is 9 tiles (3x3) per page with 0.5" margins.
+var gridLayout = pdfLayout{cols: 3, rows: 3, topMargin: 36, sideMargin: 36, gap: 12}
+
 // tilePNGsToPDF reads all PNG files in inputDir and writes them to a multi-page
 // Letter PDF (8.5x11 in) with 9 tiles (3x3) per page.
 func TilePNGsToPDF(inputDir, outPDFPath string) error {
-	entries, err := os.ReadDir(inputDir)
+	return tileSectionsToPDF([]PDFSection{{Dir: inputDir}}, outPDFPath, gridLayout)
+}
+
+// listPNGs returns the sorted paths of the PNG files directly in dir.
+func listPNGs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("read dir %q: %w", inputDir, err)
+		return nil, fmt.Errorf("read dir %q: %w", dir, err)
 	}
 
 	var pngPaths []string
@@ -26,75 +58,90 @@ func TilePNGsToPDF(inputDir, outPDFPath string) error {
 		}
 		name := e.Name()
 		if strings.HasSuffix(strings.ToLower(name), ".png") {
-			pngPaths = append(pngPaths, filepath.Join(inputDir, name))
+			pngPaths = append(pngPaths, filepath.Join(dir, name))
 		}
 	}
 	sort.Strings(pngPaths)
 	if len(pngPaths) == 0 {
-		return fmt.Errorf("no PNG files found in %q", inputDir)
+		return nil, fmt.Errorf("no PNG files found in %q", dir)
 	}
+	return pngPaths, nil
+}
 
+// tileSectionsToPDF writes the PNGs of every section, in order, to a
+// multi-page Letter PDF using layout.
+func tileSectionsToPDF(sections []PDFSection, outPDFPath string, layout pdfLayout) error {
 	// Letter size in points (1 inch = 72 points)
 	const pageW, pageH = 612.0, 792.0
 
-	// Layout settings (points)
-	const (
-		cols   = 3
-		rows   = 3
-		margin = 36.0 // 0.5"
-		gap    = 12.0
-	)
-
-	cellW := (pageW - 2*margin - float64(cols-1)*gap) / float64(cols)
-	cellH := (pageH - 2*margin - float64(rows-1)*gap) / float64(rows)
+	cols, rows := layout.cols, layout.rows
+	gap := layout.gap
+	cellW := (pageW - 2*layout.sideMargin - float64(cols-1)*gap) / float64(cols)
+	cellH := (pageH - layout.topMargin - layout.sideMargin - float64(rows-1)*gap) / float64(rows)
 
 	pdf := draw2dpdf.NewPdf("P", "pt", "Letter")
 	gc := draw2dpdf.NewGraphicContext(pdf)
 
-	for i, p := range pngPaths {
-		if i%(cols*rows) == 0 {
-			pdf.AddPage()
+	for _, section := range sections {
+		pngPaths := section.Files
+		if len(pngPaths) == 0 {
+			var err error
+			if pngPaths, err = listPNGs(section.Dir); err != nil {
+				return err
+			}
 		}
 
-		f, err := os.Open(p)
-		if err != nil {
-			return fmt.Errorf("open %q: %w", p, err)
-		}
-		img, err := png.Decode(f)
-		_ = f.Close()
-		if err != nil {
-			return fmt.Errorf("decode png %q: %w", p, err)
+		for i, p := range pngPaths {
+			if i%(cols*rows) == 0 {
+				pdf.AddPage()
+				if section.Title != "" {
+					drawSectionTitle(pdf, section.Title, i == 0, layout)
+				}
+			}
+
+			f, err := os.Open(p)
+			if err != nil {
+				return fmt.Errorf("open %q: %w", p, err)
+			}
+			img, err := png.Decode(f)
+			_ = f.Close()
+			if err != nil {
+				return fmt.Errorf("decode png %q: %w", p, err)
+			}
+
+			idxOnPage := i % (cols * rows)
+			r := idxOnPage / cols
+			c := idxOnPage % cols
+
+			x0 := layout.sideMargin + float64(c)*(cellW+gap)
+			y0 := layout.topMargin + layout.extraOffset + float64(r)*(cellH+gap)
+
+			iw := float64(img.Bounds().Dx())
+			ih := float64(img.Bounds().Dy())
+
+			var s float64
+			if layout.targetWidth > 0 {
+				s = layout.targetWidth / iw
+			} else {
+				// Scale-to-fit (preserve aspect ratio)
+				s = cellW / iw
+				if sy := cellH / ih; sy < s {
+					s = sy
+				}
+			}
+			drawW := iw * s
+			drawH := ih * s
+
+			// Center in the cell
+			x := x0 + (cellW-drawW)/2
+			y := y0 + (cellH-drawH)/2
+
+			gc.Save()
+			gc.Translate(x, y)
+			gc.Scale(s, s)
+			gc.DrawImage(img)
+			gc.Restore()
 		}
-
-		idxOnPage := i % (cols * rows)
-		r := idxOnPage / cols
-		c := idxOnPage % cols
-
-		x0 := margin + float64(c)*(cellW+gap)
-		y0 := margin + float64(r)*(cellH+gap)
-
-		iw := float64(img.Bounds().Dx())
-		ih := float64(img.Bounds().Dy())
-
-		// Scale-to-fit (preserve aspect ratio)
-		sx := cellW / iw
-		sy := cellH / ih
-		s := sx
-		if sy < sx {
-			s = sy
-		}
-		drawW := iw * s
-		drawH := ih * s
-
-		// Center in the cell
-		x := x0 + (cellW-drawW)/2
-		y := y0 + (cellH-drawH)/2
-
-		gc.Save()
-		gc.Translate(x, y)
-		gc.Scale(s, s)
-		gc.DrawImage(img)
-		gc.Restore()
 	}
 
 	if err := os.MkdirAll(filepath.Dir(outPDFPath), 0o755); err != nil {
@@ -105,3 +152,16 @@ func TilePNGsToPDF(inputDir, outPDFPath string) error {
 	}
 	return nil
 }
+
+// drawSectionTitle writes the section heading in the top margin of the
+// current page and bookmarks the first page of the section.
+func drawSectionTitle(pdf *gofpdf.Fpdf, title string, first bool, layout pdfLayout) {
+	const fontSize = 16.0
+
+	if first {
+		pdf.Bookmark(title, 0, 0)
+	}
+	pdf.SetFont("Helvetica", "B", fontSize)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Text(layout.sideMargin, layout.topMargin-fontSize/2, title)
+}
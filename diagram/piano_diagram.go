@@ -4,17 +4,11 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"image/png"
-	"log"
-	"os"
-	"path/filepath"
-	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/golang/freetype/truetype"
-	"github.com/llgcode/draw2d"
 	"github.com/llgcode/draw2d/draw2dimg"
-	"github.com/llgcode/draw2d/draw2dpdf"
+	"github.com/mrgrenier/GuitarScales/note"
 )
 
 type PianoDiagram struct {
@@ -22,206 +16,369 @@ type PianoDiagram struct {
 	scaleHeight         float64
 	canvasWidth         int
 	canvasHeight        int
-	keyWidth            float64
-	flatName            map[string]string
+	root                note.Note
+	octaves             int
+	lowKey              int
+	highKey             int
+	whiteKeyWidth       float64
+	keys                []pianoKey
 	StringFret2Interval map[int]map[string]bool
 	dest                *image.RGBA
 	gc                  *draw2dimg.GraphicContext
 }
 
+// pianoKey is the outline of one key, numbered like MIDI notes (C4 is 60).
+type pianoKey struct {
+	number int
+	black  bool
+	x, y   float64
+	w, h   float64
+}
+
 const (
 	offsetX = 0.0
 	offsetY = 150.0
+	// middleC is the key number of C4.
+	middleC = 60
+	// blackKeyWidth and blackKeyHeight are the size of a black key relative
+	// to a white one.
+	blackKeyWidth  = 0.6
+	blackKeyHeight = 0.62
+	// minWhiteKeyWidth is the narrowest a white key is drawn, in pixels; wide
+	// keyboards grow the canvas instead.
+	minWhiteKeyWidth = 40.0
+	// maxWhiteKeyLength caps the length of a white key at this many times its
+	// width, so long keyboards do not end up with needle-thin keys.
+	maxWhiteKeyLength = 5.0
 )
 
-func NewPianoDiagram() Diagram {
+// keyboardSizes are the ranges of the usual keyboards by number of keys.
+var keyboardSizes = map[int][2]int{
+	25: {48, 72},  // C3 to C5
+	37: {48, 84},  // C3 to C6
+	49: {36, 84},  // C2 to C6
+	61: {36, 96},  // C2 to C7
+	76: {28, 103}, // E1 to G7
+	88: {21, 108}, // A0 to C8
+}
+
+// PianoOption configures a PianoDiagram built by NewPianoDiagram.
+type PianoOption func(*PianoDiagram)
+
+// WithPianoRoot lays the intervals out for the key of root in place of C.
+func WithPianoRoot(root note.Note) PianoOption {
+	return func(p *PianoDiagram) {
+		p.root = root
+	}
+}
+
+// WithOctaves shows n octaves from the root, starting low enough to keep
+// middle C near the centre of the keyboard.
+func WithOctaves(n int) PianoOption {
+	return func(p *PianoDiagram) {
+		p.octaves = max(n, 1)
+	}
+}
+
+// WithKeyRange shows the keys from low to high, numbered like MIDI notes.
+// Either end that falls on a black key is widened to the next white key.
+func WithKeyRange(low, high int) PianoOption {
+	return func(p *PianoDiagram) {
+		p.lowKey, p.highKey = min(low, high), max(low, high)
+	}
+}
+
+// ParseKeyRange parses a keyboard size such as "61" or "88", or a range of
+// keys from the lowest to the highest such as "C3-C6" or "F#2-Bb5", into key
+// numbers for WithKeyRange.
+func ParseKeyRange(s string) (low, high int, err error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		r, ok := keyboardSizes[n]
+		if !ok {
+			return 0, 0, fmt.Errorf("no %d key keyboard: use 25, 37, 49, 61, 76 or 88, or a range like C3-C6", n)
+		}
+		return r[0], r[1], nil
+	}
+
+	lowName, highName, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("key range %q: want a number of keys or a range like C3-C6", s)
+	}
+	if low, err = parseKey(lowName); err != nil {
+		return 0, 0, err
+	}
+	if high, err = parseKey(highName); err != nil {
+		return 0, 0, err
+	}
+	if low >= high {
+		return 0, 0, fmt.Errorf("key range %q: %s is not below %s", s, lowName, highName)
+	}
+	return low, high, nil
+}
+
+// parseKey returns the number of a key named by its note and octave, such as
+// "C4" (60) or "Bb2".
+func parseKey(name string) (int, error) {
+	pitch, err := note.ParsePitch(name)
+	if err != nil {
+		return 0, fmt.Errorf("key: %w", err)
+	}
+	key := pitch.MIDI()
+	if key < 0 || key > 127 {
+		return 0, fmt.Errorf("key %q is out of range", name)
+	}
+	return key, nil
+}
+
+// keyName names a key by its note and octave, e.g. "C4" for 60.
+func keyName(key int) string {
+	return note.FromMIDI(key).String()
+}
+
+func NewPianoDiagram(opts ...PianoOption) (Diagram, error) {
 	scaleOctaveWidth := 6.5
 	p := &PianoDiagram{
 		scaleOctaveWidth: scaleOctaveWidth,
-		scaleHeight:      1.25,
+		scaleHeight:      1.75,
 		canvasWidth:      1188,
-		canvasHeight:     940,
-		keyWidth:         scaleOctaveWidth / 12,
+		root:             note.Note{Name: "C"},
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+
+	// Unless a range is given, show the octaves from the root, one octave
+	// starting in the fourth octave by default. The ends are widened to
+	// whole white keys.
+	if p.highKey == 0 {
+		octaves := max(p.octaves, 1)
+		p.lowKey = middleC - 12*(octaves/2) + p.root.PitchClass()
+		p.highKey = p.lowKey + 12*octaves
+	}
+	p.lowKey = whiteKeyAtOrBelow(p.lowKey)
+	p.highKey = whiteKeyAtOrAbove(p.highKey)
+	p.layoutKeys()
+
 	p.dest = image.NewRGBA(image.Rect(0, 0, p.canvasWidth, p.canvasHeight))
 	p.gc = draw2dimg.NewGraphicContext(p.dest)
 
-	draw2d.SetFontFolder("./resource/font")
-	b, err := os.ReadFile("./resource/font/wqy-zenhei.ttf")
-	if err != nil {
-		log.Fatal(err)
-	}
-	font, err := truetype.Parse(b)
-	if err != nil {
-		log.Fatal(err)
+	if err := setFont(p.gc); err != nil {
+		return nil, err
 	}
-	fontdata := draw2d.FontData{Name: "wgy-zenhei", Family: draw2d.FontFamilyMono, Style: draw2d.FontStyleNormal}
-	draw2d.RegisterFont(
-		fontdata,
-		font,
-	)
-
-	p.gc.SetFontData(fontdata)
 
+	// Each semitone above the root gets the names of its interval.
 	p.StringFret2Interval = make(map[int]map[string]bool)
-	for f := 0; f < 12; f++ {
-		p.StringFret2Interval[f] = make(map[string]bool)
+	for offset, names := range intervalNames() {
+		p.StringFret2Interval[offset] = make(map[string]bool)
+		for _, name := range names {
+			p.StringFret2Interval[offset][name] = true
+		}
 	}
 
-	p.StringFret2Interval[0]["1"] = true
-	p.StringFret2Interval[1]["b2"] = true
-	p.StringFret2Interval[2]["2"] = true
-	p.StringFret2Interval[3]["#2"] = true
-	p.StringFret2Interval[3]["b3"] = true
-	p.StringFret2Interval[4]["3"] = true
-	p.StringFret2Interval[5]["4"] = true
-	p.StringFret2Interval[6]["#4"] = true
-	p.StringFret2Interval[6]["b5"] = true
-	p.StringFret2Interval[7]["5"] = true
-	p.StringFret2Interval[8]["#5"] = true
-	p.StringFret2Interval[8]["b6"] = true
-	p.StringFret2Interval[9]["6"] = true
-	p.StringFret2Interval[10]["#6"] = true
-	p.StringFret2Interval[10]["b7"] = true
-	p.StringFret2Interval[11]["7"] = true
-
-	p.flatName = make(map[string]string)
-	p.flatName["#2"] = "b3"
-	p.flatName["#4"] = "b5"
-	p.flatName["#5"] = "b6"
-	p.flatName["#6"] = "b7"
-
-	return p
+	return p, nil
 }
 
 var _ Diagram = (*PianoDiagram)(nil)
 
-func (p *PianoDiagram) DrawDiagram() {
+// isBlackKey reports whether the key numbered key is a black key.
+func isBlackKey(key int) bool {
+	switch key % 12 {
+	case 1, 3, 6, 8, 10:
+		return true
+	}
+	return false
+}
 
-	// Draw one octave: an outer rectangle of (scaleOctaveWidth x scaleHeight),
-	// divided into 12 equal keyWidth segments.
+func whiteKeyAtOrBelow(key int) int {
+	for isBlackKey(key) {
+		key--
+	}
+	return key
+}
+
+func whiteKeyAtOrAbove(key int) int {
+	for isBlackKey(key) {
+		key++
+	}
+	return key
+}
+
+// layoutKeys works out the outline of every key from lowKey to highKey and
+// sizes the canvas to fit. The white keys share the width of the canvas,
+// which grows when they would get too narrow, and each black key sits across
+// the line between the white keys around it. The keyboard is scaleHeight
+// tall for every scaleOctaveWidth of width, up to maxWhiteKeyLength, leaving
+// room for the octave names below it.
+func (p *PianoDiagram) layoutKeys() {
+	whiteKeys := 0
+	for key := p.lowKey; key <= p.highKey; key++ {
+		if !isBlackKey(key) {
+			whiteKeys++
+		}
+	}
 
 	usableW := float64(p.canvasWidth) - 2*offsetX
-	unitToPx := usableW / p.scaleOctaveWidth
+	whiteW := usableW / float64(whiteKeys)
+	if whiteW < minWhiteKeyWidth {
+		whiteW = minWhiteKeyWidth
+		usableW = whiteW * float64(whiteKeys)
+		p.canvasWidth = int(usableW + 2*offsetX)
+	}
+	whiteH := min(usableW*p.scaleHeight/p.scaleOctaveWidth, whiteW*maxWhiteKeyLength)
+	p.whiteKeyWidth = whiteW
+	p.canvasHeight = int(offsetY + whiteH + 2*p.octaveFontSize())
+
+	p.keys = nil
+	x := offsetX
+	for key := p.lowKey; key <= p.highKey; key++ {
+		if isBlackKey(key) {
+			w := whiteW * blackKeyWidth
+			p.keys = append(p.keys, pianoKey{number: key, black: true, x: x - w/2, y: offsetY, w: w, h: whiteH * blackKeyHeight})
+			continue
+		}
+		p.keys = append(p.keys, pianoKey{number: key, x: x, y: offsetY, w: whiteW, h: whiteH})
+		x += whiteW
+	}
+}
+
+func (p *PianoDiagram) DrawDiagram() {
+
+	// Draw the white keys, then the shorter black keys over them.
+	p.drawKeys(func(key pianoKey) color.RGBA {
+		if key.black {
+			return color.RGBA{0x22, 0x22, 0x22, 0xff}
+		}
+		return color.RGBA{0xff, 0xff, 0xff, 0xff}
+	})
+
+	p.drawOctaveNames()
+
+}
 
-	w := p.scaleOctaveWidth * unitToPx
-	h := p.scaleHeight * unitToPx
+func (p *PianoDiagram) octaveFontSize() float64 {
+	return min(28, p.whiteKeyWidth*0.5)
+}
 
-	x0 := offsetX
-	y0 := offsetY
-	x1 := x0 + w
-	y1 := y0 + h
+// drawOctaveNames writes the name of every C under its key, and of the
+// lowest key when it is not a C, so the octaves can be told apart.
+func (p *PianoDiagram) drawOctaveNames() {
+	fontSize := p.octaveFontSize()
+	p.gc.SetFontSize(fontSize)
+	p.gc.SetFillColor(color.RGBA{0x44, 0x44, 0x44, 0xff})
 
+	for i, key := range p.keys {
+		if key.black || (key.number%12 != 0 && i != 0) {
+			continue
+		}
+		name := keyName(key.number)
+		left, _, right, _ := p.gc.GetStringBounds(name)
+		p.gc.FillStringAt(name, key.x+(key.w-(right-left))/2, key.y+key.h+fontSize*1.5)
+	}
+}
+
+// drawKeys fills every key with the color fill picks for it, white keys
+// first so the black keys end up on top.
+func (p *PianoDiagram) drawKeys(fill func(key pianoKey) color.RGBA) {
 	p.gc.SetStrokeColor(color.RGBA{0x44, 0x44, 0x44, 0xff})
 	p.gc.SetLineWidth(2)
 
-	// Outer rectangle
-	p.gc.BeginPath()
-	p.gc.MoveTo(x0, y0)
-	p.gc.LineTo(x1, y0)
-	p.gc.LineTo(x1, y1)
-	p.gc.LineTo(x0, y1)
-	p.gc.Close()
-	p.gc.Stroke()
-
-	// 12 segments (vertical dividers)
-	segW := p.keyWidth * unitToPx
-	for i := 1; i < 12; i++ {
-		x := x0 + float64(i)*segW
-		p.gc.BeginPath()
-		p.gc.MoveTo(x, y0)
-		p.gc.LineTo(x, y1)
-		p.gc.Stroke()
+	for _, black := range []bool{false, true} {
+		for _, key := range p.keys {
+			if key.black != black {
+				continue
+			}
+			p.gc.BeginPath() // Initialize a new path
+			p.gc.SetFillColor(fill(key))
+			p.gc.MoveTo(key.x, key.y)
+			p.gc.LineTo(key.x+key.w, key.y)
+			p.gc.LineTo(key.x+key.w, key.y+key.h)
+			p.gc.LineTo(key.x, key.y+key.h)
+			p.gc.Close()
+			p.gc.FillStroke()
+		}
 	}
+}
 
+// interval returns the interval name of key within intervalmap, "R" for the
+// root, and whether the key is in the scale at all.
+func (p *PianoDiagram) interval(key pianoKey, intervalmap map[string]string) (string, bool) {
+	offset := ((key.number-p.root.PitchClass())%12 + 12) % 12
+	var note string
+	for note = range p.StringFret2Interval[offset] {
+		if note == "1" {
+			return "R", true
+		} else if intervalmap[note] != "" {
+			return intervalmap[note], true
+		}
+	}
+	// for the notes not in the interval favor the flat name ins stead of the sharp name
+	return flatName(note), false
 }
 
 func (p *PianoDiagram) ColorScale(interval []string) {
 
-	intervalmap := make(map[string]bool)
-	for _, i := range interval {
-		intervalmap[i] = true
-	}
+	intervalmap := scaleIntervals(interval)
 
-	blankNoteColor := color.RGBA{0xee, 0xee, 0xee, 0xff}
-	blankNoteFontColor := color.RGBA{0x00, 0x00, 0x00, 0xff}
+	whiteKeyColor := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	blackKeyColor := color.RGBA{0x22, 0x22, 0x22, 0xff}
 	rootNoteColor := color.RGBA{0xff, 0x44, 0x44, 0xff}
 	rootNoteFontColor := color.RGBA{0xff, 0xff, 0xff, 0xff}
-	scaleNoteColor := color.RGBA{0x00, 0x00, 0x00, 0xff}
+	scaleNoteColor := color.RGBA{0x44, 0x77, 0xcc, 0xff}
 	scaleNoteFontColor := color.RGBA{0xff, 0xff, 0xff, 0xff}
 
-	noteColor := blankNoteColor
-	fontColor := blankNoteFontColor
-	var note string
+	p.drawKeys(func(key pianoKey) color.RGBA {
+		note, inScale := p.interval(key, intervalmap)
+		switch {
+		case note == "R":
+			return rootNoteColor
+		case inScale:
+			return scaleNoteColor
+		case key.black:
+			return blackKeyColor
+		}
+		return whiteKeyColor
+	})
 
-	usableW := float64(p.canvasWidth) - 2*offsetX
-	unitToPx := usableW / p.scaleOctaveWidth
-	w := p.scaleOctaveWidth * unitToPx
-	h := p.scaleHeight * unitToPx
-
-	segW := p.keyWidth * unitToPx
-	x0 := offsetX
-	y0 := offsetY
-	x1 := x0 + w
-	y1 := y0 + h
-
-	for i := 0; i < 12; i++ {
-		noteColor = blankNoteColor
-		fontColor = blankNoteFontColor
-		for note = range p.StringFret2Interval[i] {
-			if note == "1" {
-				noteColor = rootNoteColor
-				fontColor = rootNoteFontColor
-				note = "R"
-				break
-			} else if intervalmap[note] == true {
-				noteColor = scaleNoteColor
-				fontColor = scaleNoteFontColor
-				break
-			} else {
-				// for the notes not in the interval favor the flat name ins stead of the sharp name
-				note = p.getFlatName(note)
-			}
+	// Label the scale notes near the bottom of their keys
+	for _, key := range p.keys {
+		note, inScale := p.interval(key, intervalmap)
+		if !inScale {
+			continue
 		}
-		x := x0 + float64(i)*segW
-		x1 = x + segW
-		p.gc.BeginPath() // Initialize a new path
-		p.gc.SetFillColor(noteColor)
-		p.gc.SetStrokeColor(fontColor)
-		p.gc.MoveTo(x, y0)
-		p.gc.LineTo(x1, y0)
-		p.gc.LineTo(x1, y1)
-		p.gc.LineTo(x, y1)
-		p.gc.Close()
-		p.gc.FillStroke()
-		p.DrawInterval(note, x+segW/1.5, y0+h/2.1, segW, fontColor)
+		fontColor := scaleNoteFontColor
+		if note == "R" {
+			fontColor = rootNoteFontColor
+		}
+		p.DrawInterval(note, key.x+key.w/2, key.y+key.h-key.w/2, key.w, fontColor)
 	}
 }
 
-func (p *PianoDiagram) DrawInterval(note string, x, y, radius float64, textColor color.RGBA) {
+// DrawInterval writes the interval centred on x with its baseline at y,
+// sized to fit a key width wide.
+func (p *PianoDiagram) DrawInterval(note string, x, y, width float64, textColor color.RGBA) {
 
-	flat := string([]rune{'\u266D'})
-	sharp := string([]rune{'\u266F'})
-	var noteFontSize float64 = 34
-	var accidentalsFontSize float64 = 20
+	var noteFontSize = min(34, width*0.45)
+	var accidentalsFontSize = noteFontSize * 20 / 34
 
 	p.gc.SetFillColor(textColor)
 	p.gc.SetStrokeColor(textColor)
 
-	if strings.HasPrefix(note, "b") {
-		p.gc.SetFontSize(accidentalsFontSize)
-		p.gc.FillStringAt(flat, x-(radius+accidentalsFontSize)/3, y+accidentalsFontSize)
-		x = x + accidentalsFontSize/2
-		note = note[1:]
-	} else if strings.HasPrefix(note, "#") {
+	accidental, note := splitAccidentals(note)
+
+	p.gc.SetFontSize(noteFontSize)
+	left, _, right, _ := p.gc.GetStringBounds(note)
+	noteW := right - left
+	accidentalW := 0.0
+	if accidental != "" {
 		p.gc.SetFontSize(accidentalsFontSize)
-		p.gc.FillStringAt(sharp, x-(radius+accidentalsFontSize)/3, y+accidentalsFontSize)
-		x = x + accidentalsFontSize/2
-		note = note[1:]
+		left, _, right, _ = p.gc.GetStringBounds(accidental)
+		accidentalW = right - left
+		p.gc.FillStringAt(accidental, x-(noteW+accidentalW)/2, y-noteFontSize/2)
 	}
 
 	p.gc.SetFontSize(noteFontSize)
-	p.gc.FillStringAt(note, x-(radius+noteFontSize)/4.75, y+(radius+noteFontSize)/4.5)
+	p.gc.FillStringAt(note, x-(noteW+accidentalW)/2+accidentalW, y)
 
 }
 
@@ -252,102 +409,24 @@ func (p *PianoDiagram) SaveScaleDiagram(filename string) {
 	}
 }
 
-func (p *PianoDiagram) getFlatName(interval string) string {
-	if flatname, ok := p.flatName[interval]; ok {
-		return (flatname)
-	}
-	return interval
-}
-
 // tilePNGsToPDF reads all PNG files in inputDir and writes them to a multi-page
 // Letter PDF (8.5x11 in) with 9 tiles (3x3) per page.
 func (p *PianoDiagram) TilePNGsToPDF(inputDir, outPDFPath string) error {
-	entries, err := os.ReadDir(inputDir)
-	if err != nil {
-		return fmt.Errorf("read dir %q: %w", inputDir, err)
-	}
-
-	var pngPaths []string
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		if strings.HasSuffix(strings.ToLower(name), ".png") {
-			pngPaths = append(pngPaths, filepath.Join(inputDir, name))
-		}
-	}
-	sort.Strings(pngPaths)
-	if len(pngPaths) == 0 {
-		return fmt.Errorf("no PNG files found in %q", inputDir)
-	}
-
-	// Letter size in points (1 inch = 72 points)
-	const pageW, pageH = 612.0, 792.0
-
-	// Layout settings (points)
-	const (
-		cols        = 1
-		rows        = 3
-		topMargin   = 72.0 // 1 inch
-		sideMargin  = 36.0 // 0.5"
-		extraOffset = 36.0
-		gap         = 12.0
-	)
-
-	targetWidthPt := p.scaleOctaveWidth * 72.0
-	cellW := (pageW - 2*sideMargin - float64(cols-1)*gap) / float64(cols)
-	cellH := (pageH - topMargin - sideMargin - float64(rows-1)*gap) / float64(rows)
-
-	pdf := draw2dpdf.NewPdf("P", "pt", "Letter")
-	gc := draw2dpdf.NewGraphicContext(pdf)
-
-	for i, p := range pngPaths {
-		if i%(cols*rows) == 0 {
-			pdf.AddPage()
-		}
-
-		f, err := os.Open(p)
-		if err != nil {
-			return fmt.Errorf("open %q: %w", p, err)
-		}
-		img, err := png.Decode(f)
-		_ = f.Close()
-		if err != nil {
-			return fmt.Errorf("decode png %q: %w", p, err)
-		}
-
-		idxOnPage := i % (cols * rows)
-		r := idxOnPage / cols
-		c := idxOnPage % cols
-
-		x0 := sideMargin + float64(c)*(cellW+gap)
-		y0 := topMargin + extraOffset + float64(r)*(cellH+gap)
-
-		iw := float64(img.Bounds().Dx())
-		ih := float64(img.Bounds().Dy())
+	return p.TileSectionsToPDF([]PDFSection{{Dir: inputDir}}, outPDFPath)
+}
 
+// TileSectionsToPDF writes each section's PNGs to the PDF, three keyboards
+// per page, starting every section on a new page under its title.
+func (p *PianoDiagram) TileSectionsToPDF(sections []PDFSection, outPDFPath string) error {
+	layout := pdfLayout{
+		cols:        1,
+		rows:        3,
+		topMargin:   72.0, // 1 inch
+		sideMargin:  36.0, // 0.5"
+		extraOffset: 36.0,
+		gap:         12.0,
 		// Scale so the image width equals scaleOctaveWidth inches
-		s := targetWidthPt / iw
-		drawW := iw * s
-		drawH := ih * s
-
-		// Center in the cell
-		x := x0 + (cellW-drawW)/2
-		y := y0 + (cellH-drawH)/2
-
-		gc.Save()
-		gc.Translate(x, y)
-		gc.Scale(s, s)
-		gc.DrawImage(img)
-		gc.Restore()
-	}
-
-	if err := os.MkdirAll(filepath.Dir(outPDFPath), 0o755); err != nil {
-		return fmt.Errorf("mkdir %q: %w", filepath.Dir(outPDFPath), err)
-	}
-	if err := draw2dpdf.SaveToPdfFile(outPDFPath, pdf); err != nil {
-		return fmt.Errorf("save pdf %q: %w", outPDFPath, err)
+		targetWidth: p.scaleOctaveWidth * 72.0,
 	}
-	return nil
+	return tileSectionsToPDF(sections, outPDFPath, layout)
 }
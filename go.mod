@@ -4,10 +4,9 @@ go 1.24
 
 require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/llgcode/draw2d v0.0.0-20240627062922-0ed1ff131195
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	github.com/jung-kurt/gofpdf v1.16.2 // indirect
-	golang.org/x/image v0.28.0 // indirect
-)
+require golang.org/x/image v0.28.0 // indirect
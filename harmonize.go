@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mrgrenier/GuitarScales/chord"
+	"github.com/mrgrenier/GuitarScales/diagram"
+	"github.com/mrgrenier/GuitarScales/scale"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+// runHarmonize prints the chords built on every degree of the selected
+// scales and draws a chord box for each of them.
+func runHarmonize(args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("harmonize", flag.ExitOnError)
+	fs.StringVar(&opts.root, "root", "C", "root note of the scales, e.g. C, F#, Bb")
+	fs.StringVar(&opts.accidental, "accidental", "", "spell notes with sharp or flat (default: from -root, flat for naturals)")
+	fs.StringVar(&opts.scales, "scale", "ionian", "comma separated scale names, aliases or categories to harmonize; partial names match")
+	sevenths := fs.Bool("sevenths", false, "build seventh chords instead of triads")
+	fs.StringVar(&opts.instrument, "instrument", "guitar", "fretted instrument: "+strings.Join(tuning.InstrumentNames(), ", "))
+	tuningSpec := fs.String("tuning", "", "tuning of the instrument: a preset ("+strings.Join(tuning.Names(), ", ")+") or notes from the lowest string (default: the instrument's usual tuning)")
+	fs.StringVar(&opts.outDir, "out", "./output", "output directory")
+	fs.StringVar(&opts.format, "format", "both", "output format: png, pdf, both or none to only print the table")
+	addLibraryFlag(fs)
+	fs.Parse(args)
+
+	inst := findInstrument(opts.instrument)
+	if inst.fretted == nil {
+		return fmt.Errorf("chords are drawn for fretted instruments, not %q", opts.instrument)
+	}
+	var err error
+	if *tuningSpec != "" {
+		if opts.tuning, err = tuning.Parse(*tuningSpec); err != nil {
+			return err
+		}
+	}
+	t := inst.tuning(opts)
+
+	root, err := parseRoot(opts.root, opts.accidental)
+	if err != nil {
+		return err
+	}
+	var writePNG, writePDF bool
+	if opts.format != "none" {
+		if writePNG, writePDF, err = parseFormat(opts.format); err != nil {
+			return err
+		}
+	}
+
+	s, err := scale.NewScale(root)
+	if err != nil {
+		return err
+	}
+	scaleNames, err := filterScales(s, opts.scales)
+	if err != nil {
+		return err
+	}
+	tones := 3
+	if *sevenths {
+		tones = 4
+	}
+
+	outDir := filepath.Join(opts.outDir, inst.name, "harmony")
+	if writePDF && !writePNG {
+		if outDir, err = os.MkdirTemp("", inst.name+"-harmony"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(outDir)
+	}
+
+	var sections []diagram.PDFSection
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, scaleName := range scaleNames {
+		chords, err := chord.Harmonize(s, scaleName, tones)
+		if err != nil {
+			// Partial names and categories can pick scales without seven notes.
+			fmt.Fprintf(os.Stderr, "skipping %s: %v\n", scaleName, err)
+			continue
+		}
+
+		title := keyName(root) + " " + scaleName
+		fmt.Fprintf(w, "%s\n", title)
+		for _, dc := range chords {
+			fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", dc.Degree, dc.Numeral, chordLabel(dc), notesString(dc))
+		}
+		fmt.Fprintln(w)
+
+		if !writePNG && !writePDF {
+			continue
+		}
+		pngDir := filepath.Join(outDir, scaleName)
+		if err := os.MkdirAll(pngDir, 0o755); err != nil {
+			return err
+		}
+		var pngPaths []string
+		for _, dc := range chords {
+			c, err := chord.NewChord(dc.Root)
+			if err != nil {
+				return err
+			}
+			voicings := c.VoicingsOf(dc.Intervals, t, chord.WithLimit(1), chord.WithRootInBass())
+			if len(voicings) == 0 {
+				voicings = c.VoicingsOf(dc.Intervals, t, chord.WithLimit(1))
+			}
+			if len(voicings) == 0 {
+				fmt.Fprintf(os.Stderr, "no voicing of %s found on %s\n", chordLabel(dc), inst.name)
+				continue
+			}
+			d, err := diagram.NewChordDiagram(voicings[0], diagram.WithChordRoot(dc.Root), diagram.WithChordTuning(t))
+			if err != nil {
+				return err
+			}
+			d.DrawDiagram()
+			d.ColorScale(dc.Intervals)
+			d.DrawTitle(dc.Numeral+": "+chordLabel(dc), notesString(dc), 40, inst.titleY)
+			pngPath := filepath.Join(pngDir, fmt.Sprintf("%02d %s.png", dc.Degree, chordLabel(dc)))
+			d.SaveScaleDiagram(pngPath)
+			pngPaths = append(pngPaths, pngPath)
+		}
+		if len(pngPaths) > 0 {
+			sections = append(sections, diagram.PDFSection{Title: title, Dir: pngDir, Files: pngPaths})
+		}
+	}
+	w.Flush()
+
+	if writePDF && len(sections) > 0 {
+		name := inst.name + "_harmony.pdf"
+		d, err := diagram.NewChordDiagram(chord.Voicing{})
+		if err != nil {
+			return err
+		}
+		return d.TileSectionsToPDF(sections, filepath.Join(opts.outDir, name))
+	}
+	return nil
+}
+
+// chordLabel names a diatonic chord by its root and its name in the chord
+// package, or by its intervals when it has no name.
+func chordLabel(dc chord.DiatonicChord) string {
+	name := dc.Name
+	if name == "" {
+		name = "(" + strings.Join(dc.Intervals, " ") + ")"
+	}
+	return keyName(dc.Root) + " " + name
+}
+
+func notesString(dc chord.DiatonicChord) string {
+	var sb strings.Builder
+	for _, n := range dc.Notes {
+		sb.WriteString(n.String())
+	}
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrgrenier/GuitarScales/chord"
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+// Known returns every scale and chord a new Scale or Chord knows: the
+// built-in ones and those added with Install.
+func Known() (*Library, error) {
+	root := note.Note{Name: "C"}
+	s, err := scale.NewScale(root)
+	if err != nil {
+		return nil, err
+	}
+	c, err := chord.NewChord(root)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Library{}
+	for _, name := range s.ScaleNames() {
+		l.Scales = append(l.Scales, scale.Definition{Name: name, Intervals: s.ScaleInterval(name)})
+	}
+	for _, name := range c.ChordNames() {
+		l.Chords = append(l.Chords, scale.Definition{Name: name, Intervals: c.ChordIntervals(name)})
+	}
+	return l, nil
+}
+
+// badNameChars cannot be used in names, which end up in file names.
+const badNameChars = `/\:*?"<>|`
+
+// Check reports every problem Validate finds and more: names with doubled
+// spaces or characters file names cannot hold, intervals out of order or
+// repeated, and scales or chords with the same notes as another. Scales
+// list their intervals upwards from the root; chords stack them, so a
+// lower interval after a higher one is read an octave up, as the 2 of
+// 1 3 5 7 2 is the 9 of 1 3 5 7 9.
+func (l *Library) Check() []error {
+	var errs []error
+	if err := l.Validate(); err != nil {
+		errs = append(errs, err.(interface{ Unwrap() []error }).Unwrap()...)
+	}
+
+	interval := scale.NewInterval()
+	for _, section := range []struct {
+		kind    string
+		defs    []scale.Definition
+		stacked bool
+	}{{"scale", l.Scales, false}, {"chord", l.Chords, true}} {
+		masks := make(map[int]string)
+		for _, d := range section.defs {
+			if strings.Contains(d.Name, "  ") || strings.ContainsAny(d.Name, badNameChars) {
+				errs = append(errs, fmt.Errorf("%s %q: name has doubled spaces or one of %s", section.kind, d.Name, badNameChars))
+			}
+
+			mask, above := 0, -1
+			for _, intr := range d.Intervals {
+				semitones, err := interval.Semitones(intr)
+				if err != nil {
+					// Validate has reported it.
+					continue
+				}
+				if mask&(1<<(semitones%12)) != 0 {
+					errs = append(errs, fmt.Errorf("%s %q: %s repeats a note", section.kind, d.Name, intr))
+					continue
+				}
+				mask |= 1 << (semitones % 12)
+
+				for section.stacked && semitones <= above {
+					semitones += 12
+				}
+				if semitones <= above {
+					errs = append(errs, fmt.Errorf("%s %q: %s is out of order", section.kind, d.Name, intr))
+				}
+				above = semitones
+			}
+
+			if other, ok := masks[mask]; ok && mask != 0 {
+				errs = append(errs, fmt.Errorf("%s %q has the same notes as %q", section.kind, d.Name, other))
+			} else {
+				masks[mask] = d.Name
+			}
+		}
+	}
+	return errs
+}
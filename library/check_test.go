@@ -0,0 +1,113 @@
+package library
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+func TestCheckKnown(t *testing.T) {
+	l, err := Known()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range l.Check() {
+		t.Error(err)
+	}
+}
+
+func TestCheckModes(t *testing.T) {
+	s, err := scale.NewScale(note.Note{Name: "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, parent := range []string{"ionian", "harmonic minor", "melodic minor"} {
+		if _, err := s.AddModes(parent); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := &Library{}
+	for _, name := range s.ScaleNames() {
+		l.Scales = append(l.Scales, scale.Definition{Name: name, Intervals: s.ScaleInterval(name)})
+	}
+	for _, err := range l.Check() {
+		t.Error(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		lib  Library
+		want string
+	}{
+		{
+			name: "same notes",
+			lib: Library{Scales: []scale.Definition{
+				{Name: "first", Intervals: []string{"1", "2", "b3", "5"}},
+				{Name: "second", Intervals: []string{"1", "2", "#2", "5"}},
+			}},
+			want: `scale "second" has the same notes as "first"`,
+		},
+		{
+			name: "same chord notes",
+			lib: Library{Chords: []scale.Definition{
+				{Name: "Add9", Intervals: []string{"1", "3", "5", "9"}},
+				{Name: "Add2", Intervals: []string{"1", "2", "3", "5"}},
+			}},
+			want: `chord "Add2" has the same notes as "Add9"`,
+		},
+		{
+			name: "bad character",
+			lib: Library{Scales: []scale.Definition{
+				{Name: "major/minor", Intervals: []string{"1", "3", "5"}},
+			}},
+			want: `scale "major/minor": name has doubled spaces`,
+		},
+		{
+			name: "doubled spaces",
+			lib: Library{Chords: []scale.Definition{
+				{Name: "Major  Triad", Intervals: []string{"1", "3", "5"}},
+			}},
+			want: `chord "Major  Triad": name has doubled spaces`,
+		},
+		{
+			name: "repeated degree",
+			lib: Library{Scales: []scale.Definition{
+				{Name: "stutter", Intervals: []string{"1", "3", "3", "5"}},
+			}},
+			want: `scale "stutter": 3 repeats a note`,
+		},
+		{
+			name: "repeated note",
+			lib: Library{Chords: []scale.Definition{
+				{Name: "Minor8th", Intervals: []string{"1", "b3", "5", "8"}},
+			}},
+			want: `chord "Minor8th": 8 repeats a note`,
+		},
+		{
+			name: "out of order",
+			lib: Library{Scales: []scale.Definition{
+				{Name: "backwards", Intervals: []string{"1", "3", "2", "5"}},
+			}},
+			want: `scale "backwards": 2 is out of order`,
+		},
+		{
+			name: "invalid interval",
+			lib: Library{Scales: []scale.Definition{
+				{Name: "typo", Intervals: []string{"1", "3", "x5"}},
+			}},
+			want: `scale "typo": x5, not a valid interval`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.lib.Check()
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.want) {
+				t.Errorf("Check() = %v, want one error with %q", errs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Package library loads scale and chord definitions from JSON or YAML files
+// so they can be added to the built-in ones without changing the code.
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mrgrenier/GuitarScales/chord"
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+// Library is the contents of a definitions file, for example in YAML:
+//
+//	scales:
+//	  - name: whole tone
+//	    intervals: [1, 2, 3, "#4", "#5", b7]
+//	    category: symmetric
+//	chords:
+//	  - name: Minor7b5
+//	    intervals: [1, b3, b5, b7]
+//	    aliases: [m7b5, half diminished]
+type Library struct {
+	Scales []scale.Definition `json:"scales,omitempty" yaml:"scales,omitempty"`
+	Chords []scale.Definition `json:"chords,omitempty" yaml:"chords,omitempty"`
+}
+
+// Load reads the definitions file at path, JSON when its extension is
+// .json and YAML otherwise, and checks every definition in it.
+func Load(path string) (*Library, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l, err := Parse(data, strings.EqualFold(filepath.Ext(path), ".json"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Parse reads definitions from data, as JSON or as YAML, and checks every
+// definition in it.
+func Parse(data []byte, isJSON bool) (*Library, error) {
+	var l Library
+	var err error
+	if isJSON {
+		err = json.Unmarshal(data, &l)
+	} else {
+		err = yaml.Unmarshal(data, &l)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Validate reports every invalid definition in the library, any name given
+// to two scales or two chords, and chords given a category, tags, parent,
+// mode or description, which only scales have.
+func (l *Library) Validate() error {
+	var errs []error
+	for _, section := range []struct {
+		kind string
+		defs []scale.Definition
+	}{{"scale", l.Scales}, {"chord", l.Chords}} {
+		seen := make(map[string]bool)
+		for _, d := range section.defs {
+			if err := d.Validate(); err != nil {
+				// Name the kind of definition on each of its problems.
+				for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+					errs = append(errs, fmt.Errorf("%s %w", section.kind, err))
+				}
+			}
+			if seen[d.Name] {
+				errs = append(errs, fmt.Errorf("%s %q is defined twice", section.kind, d.Name))
+			}
+			if section.kind == "chord" && (d.Category != "" || len(d.Tags) > 0 || d.Parent != "" || d.Mode != 0 || d.Description != "") {
+				errs = append(errs, fmt.Errorf("chord %q: only scales have a category, tags, parent, mode or description", d.Name))
+			}
+			seen[d.Name] = true
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Install adds the library's scales and chords to the built-in ones,
+// replacing those of the same name. Nothing is added unless the whole
+// library is valid.
+func (l *Library) Install() error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	if err := scale.Define(l.Scales...); err != nil {
+		return err
+	}
+	return chord.Define(l.Chords...)
+}
@@ -0,0 +1,50 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/mrgrenier/GuitarScales/chord"
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+)
+
+func TestInstallAddsNothingWhenAChordIsInvalid(t *testing.T) {
+	l := Library{
+		Scales: []scale.Definition{{Name: "test whole tone", Intervals: []string{"1", "2", "3", "#4", "#5", "b7"}}},
+		Chords: []scale.Definition{{Name: "TestBroken", Intervals: []string{"1", "x3"}}},
+	}
+	if err := l.Install(); err == nil {
+		t.Fatal("Install() = nil, want an error")
+	}
+	s, err := scale.NewScale(note.Note{Name: "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Lookup("test whole tone"); ok {
+		t.Error("the scale was installed along with an invalid chord")
+	}
+}
+
+func TestChordAliases(t *testing.T) {
+	l, err := Parse([]byte("chords:\n  - name: TestCluster\n    intervals: [1, b2, 4, b7]\n    aliases: [TestCl]\n"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Install(); err != nil {
+		t.Fatal(err)
+	}
+	c, err := chord.NewChord(note.Note{Name: "C"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := c.Lookup("testcl"); !ok || name != "TestCluster" {
+		t.Errorf("Lookup(%q) = %q, %v, want TestCluster", "testcl", name, ok)
+	}
+}
+
+func TestChordScaleFieldsRejected(t *testing.T) {
+	_, err := Parse([]byte("chords:\n  - name: Odd\n    intervals: [1, 3, 5]\n    category: triads\n"), false)
+	if err == nil {
+		t.Error("Parse accepted a chord with a category")
+	}
+}
@@ -1,42 +1,712 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/mrgrenier/GuitarScales/chord"
 	"github.com/mrgrenier/GuitarScales/diagram"
+	"github.com/mrgrenier/GuitarScales/library"
 	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/position"
 	"github.com/mrgrenier/GuitarScales/scale"
+	"github.com/mrgrenier/GuitarScales/tuning"
 )
 
-func main() {
+const usage = `usage: GuitarScales <command> [flags]
+
+commands:
+  render   draw scale diagrams to PNG and/or PDF
+  list     print the scale or chord names and their notes
+  pdf      tile previously rendered PNGs into a PDF
+  chords   draw chord boxes for the voicings given
+  identify name the chord formed by notes or frets
+  harmonize list and draw the chords on every degree of a scale
+  fit      list the scales that fit a chord, or the chords in a scale
+  check    check the scale and chord definitions for mistakes
 
-	root := note.Note{Name: "C", Alternate: note.FLAT}
+Run "GuitarScales <command> -h" for the flags of a command.
+With no command, render runs with its defaults.
+`
 
-	scale := scale.NewScale(root)
-	scale_names := scale.ScaleNames()
-	for _, scaleName := range scale_names {
-		fretdiagram := diagram.NewFretBoard()
-		fretdiagram.DrawDiagram()
-		inter := scale.ScaleInterval(scaleName)
-		fretdiagram.ColorScale(inter)
-		scaleNotes := scale.GetScaleNotes(scaleName)
-		fretdiagram.DrawTitle(scaleName, scaleNotes, 40, 100)
-		fretdiagram.SaveScaleDiagram("./output/guitar/" + scaleName + ".png")
-		pianodiagram := diagram.NewPianoDiagram()
-		pianodiagram.DrawDiagram()
-		pianodiagram.ColorScale(inter)
-		pianodiagram.DrawTitle(scaleName, scaleNotes, 40, 50)
-		pianodiagram.SaveScaleDiagram("./output/piano/" + scaleName + ".png")
+// instrument ties a diagram constructor to the name used on the command
+// line and in the output paths.
+type instrument struct {
+	name       string
+	newDiagram func(opts options, root note.Note, extra ...diagram.FretBoardOption) (diagram.Diagram, error)
+	titleY     float64
+	// fretted is the preset of fretted instruments, nil for the piano.
+	fretted *tuning.Instrument
+}
 
+// instruments returns the piano and every fretted instrument preset.
+func instruments() []instrument {
+	insts := []instrument{{name: "piano", newDiagram: newPianoDiagram, titleY: 50}}
+	for _, name := range tuning.InstrumentNames() {
+		fretted, _ := tuning.LookupInstrument(name)
+		insts = append(insts, instrument{name: name, newDiagram: newFrettedDiagram(fretted), titleY: 100, fretted: &fretted})
 	}
+	return insts
+}
 
-	guitar := diagram.NewFretBoard()
-	if err := guitar.TilePNGsToPDF("./output/guitar/", "./output/guitar_scales.pdf"); err != nil {
-		log.Fatal(err)
+// newFrettedDiagram returns a constructor for fretboards of inst. The
+// -tuning flag, when given, replaces the instrument's usual tuning.
+func newFrettedDiagram(inst tuning.Instrument) func(opts options, root note.Note, extra ...diagram.FretBoardOption) (diagram.Diagram, error) {
+	return func(opts options, root note.Note, extra ...diagram.FretBoardOption) (diagram.Diagram, error) {
+		fbOpts := []diagram.FretBoardOption{diagram.WithInstrument(inst)}
+		fbOpts = append(fbOpts, flagFretBoardOptions(opts, root)...)
+		return diagram.NewGuitarDiagram(append(fbOpts, extra...)...)
+	}
+}
+
+// flagFretBoardOptions returns the fretboard options set by the flags.
+func flagFretBoardOptions(opts options, root note.Note) []diagram.FretBoardOption {
+	fbOpts := []diagram.FretBoardOption{diagram.WithRoot(root)}
+	if len(opts.tuning.Strings) > 0 {
+		fbOpts = append(fbOpts, diagram.WithTuning(opts.tuning))
+	}
+	if opts.startFret > 0 || opts.frets > 0 {
+		startFret, frets := max(opts.startFret, 1), opts.frets
+		if frets == 0 {
+			frets = min(6, diagram.MaxFrets-startFret+1)
+		}
+		fbOpts = append(fbOpts, diagram.WithFretWindow(startFret, frets))
+	}
+	return fbOpts
+}
+
+// checkFretWindow checks that the window set by -start-fret and -frets, 0
+// being unset, fits on the neck.
+func checkFretWindow(startFret, frets int) error {
+	if startFret < 0 || startFret > diagram.MaxFrets {
+		return fmt.Errorf("-start-fret %d out of range, want 1 to %d", startFret, diagram.MaxFrets)
+	}
+	if frets < 0 || frets > diagram.MaxFrets {
+		return fmt.Errorf("-frets %d out of range, want 1 to %d", frets, diagram.MaxFrets)
+	}
+	if startFret > 0 && frets > 0 && startFret+frets-1 > diagram.MaxFrets {
+		return fmt.Errorf("-start-fret %d with -frets %d goes past fret %d", startFret, frets, diagram.MaxFrets)
+	}
+	return nil
+}
+
+// tuning returns the tuning fretboards of inst are drawn in.
+func (inst instrument) tuning(opts options) tuning.Tuning {
+	if len(opts.tuning.Strings) > 0 {
+		return opts.tuning
 	}
-	piano := diagram.NewPianoDiagram()
-	if err := piano.TilePNGsToPDF("./output/piano/", "./output/piano_scales.pdf"); err != nil {
+	return inst.fretted.Tuning
+}
+
+func newPianoDiagram(opts options, root note.Note, extra ...diagram.FretBoardOption) (diagram.Diagram, error) {
+	pianoOpts := []diagram.PianoOption{diagram.WithPianoRoot(root)}
+	if opts.octaves > 0 {
+		pianoOpts = append(pianoOpts, diagram.WithOctaves(opts.octaves))
+	}
+	if opts.highKey > 0 {
+		pianoOpts = append(pianoOpts, diagram.WithKeyRange(opts.lowKey, opts.highKey))
+	}
+	return diagram.NewPianoDiagram(pianoOpts...)
+}
+
+// options holds the flags shared by the subcommands.
+type options struct {
+	root       string
+	accidental string
+	scales     string
+	instrument string
+	outDir     string
+	format     string
+	allKeys    bool
+	modes      bool
+	startFret  int
+	frets      int
+	tuning     tuning.Tuning
+	positions  string
+	neck       int
+	octaves    int
+	lowKey     int
+	highKey    int
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cmd, args := "render", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	var err error
+	switch cmd {
+	case "render":
+		err = runRender(args)
+	case "list":
+		err = runList(args)
+	case "pdf":
+		err = runPDF(args)
+	case "chords":
+		err = runChords(args)
+	case "identify":
+		err = runIdentify(args)
+	case "harmonize":
+		err = runHarmonize(args)
+	case "fit":
+		err = runFit(args)
+	case "check":
+		err = runCheck(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		log.Fatalf("unknown command %q", cmd)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
+}
+
+func newFlagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&opts.root, "root", "C", "root note of the scales, e.g. C, F#, Bb")
+	fs.StringVar(&opts.accidental, "accidental", "", "spell notes with sharp or flat (default: from -root, flat for naturals)")
+	fs.StringVar(&opts.scales, "scale", "", "comma separated scale names, aliases or categories to include; partial names match (default: all)")
+	fs.BoolVar(&opts.modes, "modes", false, "also include every mode of the scales, e.g. the seven modes of -scale \"melodic minor\"")
+	addLibraryFlag(fs)
+	return fs
+}
+
+// addLibraryFlag adds -library, which loads scale and chord definitions from
+// a file as soon as the flag is parsed. It can be given more than once.
+func addLibraryFlag(fs *flag.FlagSet) {
+	fs.Func("library", "add the scales and chords defined in a JSON or YAML `file` (repeatable)", func(path string) error {
+		l, err := library.Load(path)
+		if err != nil {
+			return err
+		}
+		return l.Install()
+	})
+}
+
+func addOutputFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.instrument, "instrument", "both", "comma separated instruments to draw: piano, "+strings.Join(tuning.InstrumentNames(), ", ")+", both (guitar and piano) or all")
+	fs.StringVar(&opts.outDir, "out", "./output", "output directory")
+	fs.BoolVar(&opts.allKeys, "all-keys", false, "use all twelve keys, one directory and PDF section per key")
+}
+
+func runRender(args []string) error {
+	var opts options
+	fs := newFlagSet("render", &opts)
+	addOutputFlags(fs, &opts)
+	fs.StringVar(&opts.format, "format", "both", "output format: png, pdf or both")
+	fs.IntVar(&opts.startFret, "start-fret", 0, "first fret shown on the guitar (default: the root on the second fret of the low string)")
+	fs.IntVar(&opts.frets, "frets", 0, "number of frets shown on the guitar, up to 24 (default 6)")
+	tuningSpec := fs.String("tuning", "", "tuning of fretted instruments: a preset ("+strings.Join(tuning.Names(), ", ")+") or notes from the lowest string, e.g. \"D A D G B E\" (default: the instrument's usual tuning)")
+	fs.StringVar(&opts.positions, "positions", "", "draw each scale as positions on fretted instruments: caged, 3nps or all")
+	fs.IntVar(&opts.neck, "neck", 0, "also map each scale over the whole neck of fretted instruments, up to this fret (22 or 24)")
+	fs.IntVar(&opts.octaves, "octaves", 0, "number of octaves shown on the piano, from the root (default 1)")
+	keys := fs.String("keys", "", "keys shown on the piano: a keyboard size (25, 37, 49, 61, 76 or 88) or a range such as C3-C6")
+	fs.Parse(args)
+
+	var err error
+	if err := checkFretWindow(opts.startFret, opts.frets); err != nil {
+		return err
+	}
+	if *tuningSpec != "" {
+		if opts.tuning, err = tuning.Parse(*tuningSpec); err != nil {
+			return err
+		}
+	}
+	if *keys != "" {
+		if opts.octaves > 0 {
+			return fmt.Errorf("use -octaves or -keys, not both")
+		}
+		if opts.lowKey, opts.highKey, err = diagram.ParseKeyRange(*keys); err != nil {
+			return err
+		}
+	}
+
+	insts, err := selectInstruments(opts.instrument)
+	if err != nil {
+		return err
+	}
+	writePNG, writePDF, err := parseFormat(opts.format)
+	if err != nil {
+		return err
+	}
+	roots, err := selectRoots(opts)
+	if err != nil {
+		return err
+	}
+	// The PDF sections need to know the scales picked, with any modes
+	// added by -modes, to sort the diagrams into categories.
+	catScale, err := scale.NewScale(note.Note{Name: "C"})
+	if err != nil {
+		return err
+	}
+	if _, err := selectScales(catScale, opts); err != nil {
+		return err
+	}
+
+	for _, inst := range insts {
+		outDir := filepath.Join(opts.outDir, inst.name)
+		if !writePNG {
+			// The PDF is tiled from PNGs on disk, so draw them somewhere
+			// temporary when only the PDF is wanted.
+			if outDir, err = os.MkdirTemp("", inst.name); err != nil {
+				return err
+			}
+			defer os.RemoveAll(outDir)
+		}
+
+		var sections []diagram.PDFSection
+		for _, root := range roots {
+			pngDir := outDir
+			if opts.allKeys {
+				pngDir = filepath.Join(outDir, keyName(root))
+			}
+			pngPaths, err := renderScales(inst, opts, root, pngDir)
+			if err != nil {
+				return err
+			}
+			catSections, err := categorySections(catScale, pngDir, sectionTitle(root, opts.allKeys), pngPaths)
+			if err != nil {
+				return err
+			}
+			sections = append(sections, catSections...)
+		}
 
+		if writePDF {
+			d, err := inst.newDiagram(opts, note.Note{})
+			if err != nil {
+				return err
+			}
+			if err := d.TileSectionsToPDF(sections, pdfPath(opts, inst)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderScales draws every scale selected by -scale in the key of root,
+// saves them as PNGs in pngDir and returns their paths.
+func renderScales(inst instrument, opts options, root note.Note, pngDir string) ([]string, error) {
+	scale, err := scale.NewScale(root)
+	if err != nil {
+		return nil, err
+	}
+	scaleNames, err := selectScales(scale, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(pngDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var pngPaths []string
+	save := func(d diagram.Diagram, name string) {
+		pngPath := filepath.Join(pngDir, name)
+		d.SaveScaleDiagram(pngPath)
+		pngPaths = append(pngPaths, pngPath)
+	}
+
+	for _, scaleName := range scaleNames {
+		if opts.neck > 0 && inst.fretted != nil {
+			d, err := inst.newDiagram(opts, root, diagram.WithFullNeck(opts.neck))
+			if err != nil {
+				return nil, err
+			}
+			d.DrawDiagram()
+			d.ColorScale(scale.ScaleInterval(scaleName))
+			d.DrawTitle(scaleName+" - full neck", scale.GetScaleNotes(scaleName), 40, inst.titleY)
+			save(d, scaleName+" - 00 full neck.png")
+		}
+
+		if opts.positions == "" || inst.fretted == nil {
+			d, err := inst.newDiagram(opts, root)
+			if err != nil {
+				return nil, err
+			}
+			d.DrawDiagram()
+			d.ColorScale(scale.ScaleInterval(scaleName))
+			d.DrawTitle(scaleName, scale.GetScaleNotes(scaleName), 40, inst.titleY)
+			save(d, scaleName+".png")
+			continue
+		}
+
+		positions, err := scalePositions(opts.positions, scale, scaleName, inst.tuning(opts))
+		if err != nil {
+			return nil, err
+		}
+		for i, pos := range positions {
+			d, err := inst.newDiagram(opts, root, diagram.WithFretWindow(pos.StartFret, pos.NumFrets), diagram.WithPattern(pos.Frets))
+			if err != nil {
+				return nil, err
+			}
+			d.DrawDiagram()
+			d.ColorScale(scale.ScaleInterval(scaleName))
+			d.DrawTitle(scaleName+" - "+pos.Name, scale.GetScaleNotes(scaleName), 40, inst.titleY)
+			save(d, fmt.Sprintf("%s - %02d %s.png", scaleName, i+1, pos.Name))
+		}
+	}
+	return pngPaths, nil
+}
+
+// scalePositions returns the positions of a scale asked for by -positions:
+// caged, 3nps or all.
+func scalePositions(system string, s *scale.Scale, scaleName string, t tuning.Tuning) ([]position.Position, error) {
+	var positions []position.Position
+	if system == "caged" || system == "all" {
+		caged, err := position.CAGED(s, scaleName, t)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, caged...)
+	}
+	if system == "3nps" || system == "all" {
+		threeNPS, err := position.ThreeNotesPerString(s, scaleName, t)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, threeNPS...)
+	}
+	if positions == nil {
+		return nil, fmt.Errorf("unknown position system %q, want caged, 3nps or all", system)
+	}
+	return positions, nil
+}
+
+func runList(args []string) error {
+	var opts options
+	fs := newFlagSet("list", &opts)
+	chords := fs.Bool("chords", false, "list the chord names and their notes instead of the scales")
+	byCategory := fs.Bool("categories", false, "group the scales by category and show their aliases and descriptions")
+	fs.Parse(args)
+
+	root, err := parseRoot(opts.root, opts.accidental)
+	if err != nil {
+		return err
+	}
+	if *chords {
+		c, err := chord.NewChord(root)
+		if err != nil {
+			return err
+		}
+		for _, chordName := range c.ChordNames() {
+			fmt.Printf("%-22s %s\n", chordName, c.GetChordNotes(chordName))
+		}
+		return nil
+	}
+	scale, err := scale.NewScale(root)
+	if err != nil {
+		return err
+	}
+	scaleNames, err := selectScales(scale, opts)
+	if err != nil {
+		return err
+	}
+	if !*byCategory {
+		for _, scaleName := range scaleNames {
+			fmt.Printf("%-22s %s\n", scaleName, scale.GetScaleNotes(scaleName))
+		}
+		return nil
+	}
+
+	selected := make(map[string]bool)
+	for _, scaleName := range scaleNames {
+		selected[scaleName] = true
+	}
+	for _, category := range scale.Categories() {
+		var inCategory []string
+		for _, scaleName := range scale.ScalesIn(category) {
+			if selected[scaleName] {
+				inCategory = append(inCategory, scaleName)
+			}
+		}
+		if len(inCategory) == 0 {
+			continue
+		}
+		fmt.Printf("%s\n", category)
+		for _, scaleName := range inCategory {
+			info := scale.Info(scaleName)
+			fmt.Printf("  %-22s %s\n", scaleName, scale.GetScaleNotes(scaleName))
+			if len(info.Aliases) > 0 {
+				fmt.Printf("  %-22s also: %s\n", "", strings.Join(info.Aliases, ", "))
+			}
+			if info.Parent != "" && info.Parent != scaleName {
+				fmt.Printf("  %-22s mode %d of %s\n", "", info.Mode, info.Parent)
+			}
+			if info.Description != "" {
+				fmt.Printf("  %-22s %s\n", "", info.Description)
+			}
+		}
+	}
+	return nil
+}
+
+func runPDF(args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("pdf", flag.ExitOnError)
+	addOutputFlags(fs, &opts)
+	fs.StringVar(&opts.accidental, "accidental", "", "with -all-keys, the accidental the keys were rendered with: sharp or flat (default: as render spells them)")
+	fs.Parse(args)
+
+	insts, err := selectInstruments(opts.instrument)
+	if err != nil {
+		return err
+	}
+	catScale, err := scale.NewScale(note.Note{Name: "C"})
+	if err != nil {
+		return err
+	}
+	for _, inst := range insts {
+		pngDir := filepath.Join(opts.outDir, inst.name)
+		dirs, titles := []string{pngDir}, []string{""}
+		if opts.allKeys {
+			roots, err := selectRoots(opts)
+			if err != nil {
+				return err
+			}
+			dirs, titles = nil, nil
+			for _, root := range roots {
+				dirs = append(dirs, filepath.Join(pngDir, keyName(root)))
+				titles = append(titles, sectionTitle(root, true))
+			}
+		}
+		var sections []diagram.PDFSection
+		for i, dir := range dirs {
+			// The pdf command tiles whatever an earlier render left on disk.
+			pngPaths, err := filepath.Glob(filepath.Join(dir, "*.png"))
+			if err != nil {
+				return err
+			}
+			catSections, err := categorySections(catScale, dir, titles[i], pngPaths)
+			if err != nil {
+				return err
+			}
+			sections = append(sections, catSections...)
+		}
+		d, err := inst.newDiagram(opts, note.Note{})
+		if err != nil {
+			return err
+		}
+		if err := d.TileSectionsToPDF(sections, pdfPath(opts, inst)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectRoots returns the roots to render: the twelve keys with -all-keys,
+// otherwise the -root flag alone. An explicit -accidental overrides the
+// spelling of every key.
+func selectRoots(opts options) ([]note.Note, error) {
+	if !opts.allKeys {
+		root, err := parseRoot(opts.root, opts.accidental)
+		if err != nil {
+			return nil, err
+		}
+		return []note.Note{root}, nil
+	}
+
+	keys := note.Keys()
+	if opts.accidental != "" {
+		for i := range keys {
+			root, err := parseRoot(keys[i].Name, opts.accidental)
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = root
+		}
+	}
+	return keys, nil
+}
+
+// keyName is the root as spelled in titles, used for the per-key directories.
+func keyName(root note.Note) string {
+	return strings.TrimSpace(root.String())
+}
+
+// categorySections splits the scale diagrams pngPaths in dir into a PDF
+// section per category of the scales of s, titled with the category after
+// title. The scale of a diagram is the start of its file name, up to any
+// " - ".
+func categorySections(s *scale.Scale, dir, title string, pngPaths []string) ([]diagram.PDFSection, error) {
+	if len(pngPaths) == 0 {
+		return nil, fmt.Errorf("no PNG files found in %q", dir)
+	}
+	sort.Strings(pngPaths)
+
+	byCategory := make(map[string][]string)
+	for _, p := range pngPaths {
+		scaleName, _, _ := strings.Cut(strings.TrimSuffix(filepath.Base(p), ".png"), " - ")
+		category := s.Info(scaleName).Category
+		byCategory[category] = append(byCategory[category], p)
+	}
+
+	var sections []diagram.PDFSection
+	for _, category := range append(s.Categories(), "other") {
+		files := byCategory[category]
+		if len(files) == 0 {
+			continue
+		}
+		delete(byCategory, category)
+		heading := strings.ToUpper(category[:1]) + category[1:]
+		if title != "" {
+			heading = title + " - " + heading
+		}
+		sections = append(sections, diagram.PDFSection{Title: heading, Dir: dir, Files: files})
+	}
+	return sections, nil
+}
+
+func sectionTitle(root note.Note, allKeys bool) string {
+	if !allKeys {
+		return ""
+	}
+	return "Key of " + keyName(root)
+}
+
+func pdfPath(opts options, inst instrument) string {
+	if opts.allKeys {
+		return filepath.Join(opts.outDir, inst.name+"_scales_all_keys.pdf")
+	}
+	return filepath.Join(opts.outDir, inst.name+"_scales.pdf")
+}
+
+// parseRoot turns the -root and -accidental flags into a note. Without an
+// explicit accidental, the spelling of the root decides, so Cb and E# are
+// kept, and naturals use flats.
+func parseRoot(name, accidental string) (note.Note, error) {
+	root, err := note.Parse(name)
+	if err != nil {
+		return note.Note{}, fmt.Errorf("root: %w", err)
+	}
+
+	switch strings.ToLower(accidental) {
+	case "sharp", "#":
+		root = note.Note{Name: root.Name, Alternate: note.SHARP}
+	case "flat", "b":
+		root = note.Note{Name: root.Name, Alternate: note.FLAT}
+	case "":
+		if !strings.Contains(name, "#") {
+			root.SetAlternate(note.FLAT)
+		}
+	default:
+		return note.Note{}, fmt.Errorf("unknown accidental %q, want sharp or flat", accidental)
+	}
+	return root, nil
+}
+
+// selectInstruments returns the instruments named in the comma separated
+// list, where "both" means guitar and piano and "all" means every one.
+func selectInstruments(list string) ([]instrument, error) {
+	if list == "all" {
+		return instruments(), nil
+	}
+
+	var selected []instrument
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "both" {
+			selected = append(selected, findInstrument("guitar"), findInstrument("piano"))
+			continue
+		}
+		if inst := findInstrument(name); inst.newDiagram != nil {
+			selected = append(selected, inst)
+			continue
+		}
+		return nil, fmt.Errorf("unknown instrument %q", name)
+	}
+	return selected, nil
+}
+
+func findInstrument(name string) instrument {
+	for _, inst := range instruments() {
+		if inst.name == name {
+			return inst
+		}
+	}
+	return instrument{}
+}
+
+func parseFormat(format string) (png, pdf bool, err error) {
+	switch format {
+	case "png":
+		return true, false, nil
+	case "pdf":
+		return false, true, nil
+	case "both":
+		return true, true, nil
+	}
+	return false, false, fmt.Errorf("unknown format %q, want png, pdf or both", format)
+}
+
+// selectScales returns the scales picked by -scale, each followed by its
+// modes with -modes. The modes are added to s so they can be drawn.
+func selectScales(s *scale.Scale, opts options) ([]string, error) {
+	scaleNames, err := filterScales(s, opts.scales)
+	if err != nil || !opts.modes {
+		return scaleNames, err
+	}
+	var withModes []string
+	seen := make(map[string]bool)
+	for _, scaleName := range scaleNames {
+		modes, err := s.AddModes(scaleName)
+		if err != nil {
+			return nil, err
+		}
+		for _, mode := range modes {
+			if !seen[mode] {
+				seen[mode] = true
+				withModes = append(withModes, mode)
+			}
+		}
+	}
+	return withModes, nil
+}
+
+// filterScales keeps the scales of s whose names contain any of the comma
+// separated terms in filter, along with those known by a term as an alias
+// and every scale of a category named by a term. An empty filter keeps
+// every scale.
+func filterScales(s *scale.Scale, filter string) ([]string, error) {
+	if strings.TrimSpace(filter) == "" {
+		return s.ScaleNames(), nil
+	}
+	matched := make(map[string]bool)
+	for _, term := range strings.Split(filter, ",") {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" {
+			continue
+		}
+		if name, ok := s.Lookup(term); ok {
+			matched[name] = true
+		}
+		for _, name := range s.ScalesIn(term) {
+			matched[name] = true
+		}
+		for _, name := range s.ScaleNames() {
+			if strings.Contains(name, term) {
+				matched[name] = true
+			}
+		}
+	}
+
+	var kept []string
+	for _, name := range s.ScaleNames() {
+		if matched[name] {
+			kept = append(kept, name)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("no scales match %q", filter)
+	}
+	return kept, nil
 }
@@ -1,5 +1,11 @@
 package note
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 var altName = map[string]string{
 	"A":  "A",
 	"A#": "Bb",
@@ -15,6 +21,22 @@ var altName = map[string]string{
 	"G#": "Ab",
 }
 
+// pitchClass is the number of semitones above C of each note name.
+var pitchClass = map[string]int{
+	"C":  0,
+	"C#": 1,
+	"D":  2,
+	"D#": 3,
+	"E":  4,
+	"F":  5,
+	"F#": 6,
+	"G":  7,
+	"G#": 8,
+	"A":  9,
+	"A#": 10,
+	"B":  11,
+}
+
 type ALTERNATE_NAME int
 
 const (
@@ -25,6 +47,10 @@ const (
 type Note struct {
 	Name      string
 	Alternate ALTERNATE_NAME
+	// Spelling, when set, is how the note is written in place of Name and
+	// Alternate, for the names a key can need that those cannot give, such
+	// as "Cb", "E#" or "F##".
+	Spelling string
 }
 
 func (note *Note) SetAlternate(alternate ALTERNATE_NAME) {
@@ -32,6 +58,9 @@ func (note *Note) SetAlternate(alternate ALTERNATE_NAME) {
 }
 
 func (note Note) String() string {
+	if note.Spelling != "" {
+		return note.Spelling + " "
+	}
 	var name string
 	switch note.Alternate {
 	case SHARP:
@@ -43,3 +72,177 @@ func (note Note) String() string {
 	}
 	return name + " "
 }
+
+// PitchClass returns the number of semitones the note is above C, 0 to 11.
+func (note Note) PitchClass() int {
+	return pitchClass[note.Name]
+}
+
+// Parse returns the note named by s: a letter, upper or lower case, then
+// any sharps or flats as FromName reads them, e.g. "Eb", "f#" or "Bbb". A
+// trailing octave number such as the 4 of "F#4" is accepted and checked,
+// though a Note does not keep it.
+func Parse(s string) (Note, error) {
+	name := strings.TrimSpace(s)
+	if name == "" {
+		return Note{}, fmt.Errorf("empty note name")
+	}
+	name = strings.ToUpper(name[:1]) + name[1:]
+	if letterIndex(name[0]) < 0 {
+		return Note{}, fmt.Errorf("note %q: %q is not a note letter (A to G)", s, name[:1])
+	}
+
+	if i := strings.IndexAny(name, "-0123456789"); i > 0 {
+		if _, err := parseOctave(s, name[i:]); err != nil {
+			return Note{}, err
+		}
+		name = name[:i]
+	}
+
+	n, ok := FromName(name)
+	if !ok {
+		return Note{}, fmt.Errorf("note %q: bad accidental %q, want #, b or x", s, name[1:])
+	}
+	return n, nil
+}
+
+// parseOctave reads the octave number of the note named s, -1 to 9 like
+// the octaves of MIDI notes.
+func parseOctave(s, octave string) (int, error) {
+	o, err := strconv.Atoi(octave)
+	if err != nil || o < -1 || o > 9 {
+		return 0, fmt.Errorf("note %q: bad octave %q", s, octave)
+	}
+	return o, nil
+}
+
+// letters are the note letters in order from C, each with the pitch class
+// of its natural.
+var letters = []struct {
+	letter byte
+	pitch  int
+}{{'C', 0}, {'D', 2}, {'E', 4}, {'F', 5}, {'G', 7}, {'A', 9}, {'B', 11}}
+
+// sharpNames are the note names by pitch class.
+var sharpNames = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// FromName returns the note for a name made of a letter and any number of
+// sharps or flats: "C#", "Db", "Cb", "E#", "F##" or "Bbb" ("x" is read as a
+// double sharp). The Alternate is set to match the accidental that was
+// given, and Spelling when the name is not one of the usual twelve.
+func FromName(name string) (Note, bool) {
+	if name == "" {
+		return Note{}, false
+	}
+	l := letterIndex(name[0])
+	if l < 0 {
+		return Note{}, false
+	}
+
+	shift := 0
+	for _, r := range name[1:] {
+		switch r {
+		case '#':
+			shift++
+		case 'x':
+			shift += 2
+		case 'b':
+			shift--
+		default:
+			return Note{}, false
+		}
+	}
+	return spelled(name[:1], l, shift), true
+}
+
+// Letter returns the letter the note is written with, e.g. 'D' for Db.
+func (note Note) Letter() byte {
+	name := strings.TrimSpace(note.String())
+	if name == "" {
+		return 0
+	}
+	return name[0]
+}
+
+// Spell returns the note semitones above note, written with the letter
+// degree-1 letters above note's, using double sharps or flats when needed.
+// The third above D (degree 3, 4 semitones) is F#, never Gb, and the
+// seventh above F# (degree 7, 11 semitones) is E#.
+func (note Note) Spell(degree, semitones int) Note {
+	l := letterIndex(note.Letter())
+	if l < 0 {
+		return note
+	}
+	target := ((l+degree-1)%7 + 7) % 7
+	pitch := ((note.PitchClass()+semitones)%12 + 12) % 12
+
+	shift := ((pitch-letters[target].pitch)%12 + 12) % 12
+	if shift > 6 {
+		shift -= 12
+	}
+	if shift == 0 {
+		// A natural keeps the accidentals of the note it is spelled from.
+		n := spelled(string(letters[target].letter), target, 0)
+		n.Alternate = note.Alternate
+		return n
+	}
+	return spelled(string(letters[target].letter), target, shift)
+}
+
+// spelled returns the note written as letter l raised by shift semitones.
+func spelled(letter string, l, shift int) Note {
+	pitch := ((letters[l].pitch+shift)%12 + 12) % 12
+	n := Note{Name: sharpNames[pitch], Alternate: SHARP}
+	if shift < 0 {
+		n.Alternate = FLAT
+	}
+
+	name := letter
+	switch {
+	case shift > 0:
+		name += strings.Repeat("#", shift)
+	case shift < 0:
+		name += strings.Repeat("b", -shift)
+	}
+	if strings.TrimSpace(n.String()) != name {
+		n.Spelling = name
+	}
+	return n
+}
+
+// Valid reports whether the note is one of the twelve the scales and chords
+// are built from: its Name is a sharp or natural name such as "C#", not a
+// flat such as "Db".
+func (note Note) Valid() bool {
+	_, ok := altName[note.Name]
+	return ok
+}
+
+func letterIndex(letter byte) int {
+	for i, l := range letters {
+		if l.letter == letter {
+			return i
+		}
+	}
+	return -1
+}
+
+// Keys returns the twelve roots in chromatic order from C, each spelled with
+// the accidental its key signature uses: sharps for G, D, A, E, B and F#,
+// flats for F, Bb, Eb, Ab and Db. C has neither and is spelled with flats.
+func Keys() []Note {
+	return []Note{
+		{Name: "C", Alternate: FLAT},
+		{Name: "C#", Alternate: FLAT},
+		{Name: "D", Alternate: SHARP},
+		{Name: "D#", Alternate: FLAT},
+		{Name: "E", Alternate: SHARP},
+		{Name: "F", Alternate: FLAT},
+		{Name: "F#", Alternate: SHARP},
+		{Name: "G", Alternate: SHARP},
+		{Name: "G#", Alternate: FLAT},
+		{Name: "A", Alternate: SHARP},
+		{Name: "A#", Alternate: FLAT},
+		{Name: "B", Alternate: SHARP},
+	}
+}
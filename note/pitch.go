@@ -0,0 +1,81 @@
+package note
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// A4 is the usual tuning reference, the frequency of A4 in Hz.
+const A4 = 440.0
+
+// Pitch is a note in a given octave, numbered the scientific way with C4 as
+// middle C. The octave goes with the letter, so B#3 and C4 sound the same
+// and Cb4 is a semitone below C4.
+type Pitch struct {
+	Note   Note
+	Octave int
+}
+
+// ParsePitch returns the pitch named by s: a note as Parse reads it followed
+// by its octave, e.g. "C4", "F#2" or "Bb-1".
+func ParsePitch(s string) (Pitch, error) {
+	name := strings.TrimSpace(s)
+	i := strings.IndexAny(name, "-0123456789")
+	if i <= 0 {
+		return Pitch{}, fmt.Errorf("pitch %q: want a note and an octave, e.g. C4", s)
+	}
+	n, err := Parse(name[:i])
+	if err != nil {
+		return Pitch{}, err
+	}
+	octave, err := parseOctave(s, name[i:])
+	if err != nil {
+		return Pitch{}, err
+	}
+	return Pitch{Note: n, Octave: octave}, nil
+}
+
+// FromMIDI returns the pitch of a MIDI note number, 60 being C4, named with
+// sharps.
+func FromMIDI(number int) Pitch {
+	octave := number/12 - 1
+	if number < 0 && number%12 != 0 {
+		octave--
+	}
+	return Pitch{Note: Note{Name: sharpNames[(number%12+12)%12]}, Octave: octave}
+}
+
+// MIDI returns the MIDI note number of the pitch, 60 for C4 and 69 for A4.
+func (p Pitch) MIDI() int {
+	l := letterIndex(p.Note.Letter())
+	if l < 0 {
+		return (p.Octave+1)*12 + p.Note.PitchClass()
+	}
+	// The accidental can move the pitch out of its letter's octave, as
+	// with Cb4 and B#3.
+	shift := ((p.Note.PitchClass()-letters[l].pitch)%12 + 12) % 12
+	if shift > 6 {
+		shift -= 12
+	}
+	return (p.Octave+1)*12 + letters[l].pitch + shift
+}
+
+// Frequency returns the frequency of the pitch in Hz in equal temperament,
+// tuned so that A4 sounds at a4, e.g. A4 or 432.
+func (p Pitch) Frequency(a4 float64) float64 {
+	return a4 * math.Pow(2, float64(p.MIDI()-69)/12)
+}
+
+// Transpose returns the pitch semitones higher, or lower when semitones is
+// negative, named with the same kind of accidental as p.
+func (p Pitch) Transpose(semitones int) Pitch {
+	t := FromMIDI(p.MIDI() + semitones)
+	t.Note.Alternate = p.Note.Alternate
+	return t
+}
+
+// String names the pitch by its note and octave, e.g. "Eb4".
+func (p Pitch) String() string {
+	return strings.TrimSpace(p.Note.String()) + fmt.Sprint(p.Octave)
+}
@@ -0,0 +1,99 @@
+package note
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPitchMIDI(t *testing.T) {
+	tests := []struct {
+		pitch string
+		midi  int
+		name  string
+	}{
+		{pitch: "C-1", midi: 0, name: "C-1"},
+		{pitch: "C4", midi: 60, name: "C4"},
+		{pitch: "A4", midi: 69, name: "A4"},
+		{pitch: "Bb3", midi: 58, name: "A#3"},
+		{pitch: "G9", midi: 127, name: "G9"},
+		{pitch: "Cb4", midi: 59, name: "B3"},
+		{pitch: "B#3", midi: 60, name: "C4"},
+		{pitch: "E#4", midi: 65, name: "F4"},
+		{pitch: "Fb4", midi: 64, name: "E4"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePitch(tt.pitch)
+		if err != nil {
+			t.Errorf("ParsePitch(%q): %v", tt.pitch, err)
+			continue
+		}
+		if got := p.MIDI(); got != tt.midi {
+			t.Errorf("%s MIDI() = %d, want %d", tt.pitch, got, tt.midi)
+		}
+		if got := FromMIDI(tt.midi).String(); got != tt.name {
+			t.Errorf("FromMIDI(%d) = %s, want %s", tt.midi, got, tt.name)
+		}
+		if got := FromMIDI(tt.midi).MIDI(); got != tt.midi {
+			t.Errorf("FromMIDI(%d).MIDI() = %d", tt.midi, got)
+		}
+	}
+}
+
+func TestParsePitchErrors(t *testing.T) {
+	for _, s := range []string{"", "C", "4", "H4", "C10", "C-2", "C#x"} {
+		if p, err := ParsePitch(s); err == nil {
+			t.Errorf("ParsePitch(%q) = %v, want an error", s, p)
+		}
+	}
+}
+
+func TestPitchFrequency(t *testing.T) {
+	tests := []struct {
+		pitch string
+		a4    float64
+		want  float64
+	}{
+		{pitch: "A4", a4: A4, want: 440},
+		{pitch: "A3", a4: A4, want: 220},
+		{pitch: "A5", a4: A4, want: 880},
+		{pitch: "C4", a4: A4, want: 261.626},
+		{pitch: "E2", a4: A4, want: 82.407},
+		{pitch: "A4", a4: 432, want: 432},
+		{pitch: "C4", a4: 432, want: 256.869},
+	}
+	for _, tt := range tests {
+		p, err := ParsePitch(tt.pitch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.Frequency(tt.a4); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("%s Frequency(%g) = %.3f, want %.3f", tt.pitch, tt.a4, got, tt.want)
+		}
+	}
+}
+
+func TestPitchTranspose(t *testing.T) {
+	tests := []struct {
+		pitch     string
+		semitones int
+		want      string
+	}{
+		{pitch: "B3", semitones: 1, want: "C4"},
+		{pitch: "C4", semitones: -1, want: "B3"},
+		{pitch: "A4", semitones: 12, want: "A5"},
+		{pitch: "E2", semitones: 0, want: "E2"},
+		{pitch: "G#4", semitones: 5, want: "C#5"},
+		{pitch: "Eb4", semitones: -3, want: "C4"},
+		{pitch: "Bb3", semitones: 3, want: "Db4"},
+		{pitch: "C0", semitones: -13, want: "B-2"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePitch(tt.pitch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.Transpose(tt.semitones).String(); got != tt.want {
+			t.Errorf("%s Transpose(%d) = %s, want %s", tt.pitch, tt.semitones, got, tt.want)
+		}
+	}
+}
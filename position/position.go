@@ -0,0 +1,205 @@
+package position
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mrgrenier/GuitarScales/scale"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+// maxFret is the highest fret a position may use.
+const maxFret = 24
+
+// Position is one fingering of a scale on the neck.
+type Position struct {
+	Name      string
+	StartFret int
+	NumFrets  int
+	// Frets lists the frets played on each string, lowest string first.
+	Frets [][]int
+}
+
+// cagedShape places a CAGED shape relative to the root on the lowest string.
+type cagedShape struct {
+	name     string
+	offset   int
+	numFrets int
+}
+
+// cagedShapes in the order they climb the neck. The offsets are the first
+// fret of each shape measured from the root on the lowest string in
+// standard tuning, e.g. the E shape starts a fret below it.
+var cagedShapes = []cagedShape{
+	{name: "C shape", offset: 4, numFrets: 4},
+	{name: "A shape", offset: 6, numFrets: 4},
+	{name: "G shape", offset: 8, numFrets: 5},
+	{name: "E shape", offset: -1, numFrets: 4},
+	{name: "D shape", offset: 2, numFrets: 4},
+}
+
+// CAGED returns the five CAGED positions of the scale called name. Each one
+// plays every scale note in its window of frets, which is widened up the
+// neck when the shape's usual window misses a note. The shapes are named for
+// standard tuning, placed from the root on the lowest sounding string, and
+// keep the same windows in other tunings.
+func CAGED(s *scale.Scale, name string, t tuning.Tuning) ([]Position, error) {
+	pitches, err := scalePitches(s, name)
+	if err != nil {
+		return nil, err
+	}
+	opens := openPitches(t)
+	lowest := t.Strings[slices.Index(opens, 0)].PitchClass()
+	rootFret := (s.Root().PitchClass() - lowest + 12) % 12
+
+	var positions []Position
+	for _, shape := range cagedShapes {
+		start := (rootFret + shape.offset + 12) % 12
+		if start == 0 {
+			start = 12
+		}
+		pos := Position{Name: shape.name, StartFret: start}
+		for numFrets := shape.numFrets; start+numFrets-1 <= maxFret; numFrets++ {
+			pos.NumFrets = numFrets
+			if pos.fill(t, pitches) {
+				break
+			}
+		}
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
+
+// fill sets the frets of the position to the scale notes in its window and
+// reports whether every pitch of the scale is among them.
+func (pos *Position) fill(t tuning.Tuning, pitches map[int]bool) bool {
+	pos.Frets = nil
+	played := make(map[int]bool)
+	for _, open := range t.Strings {
+		var frets []int
+		for fret := pos.StartFret; fret < pos.StartFret+pos.NumFrets; fret++ {
+			if pitch := (open.PitchClass() + fret) % 12; pitches[pitch] {
+				frets = append(frets, fret)
+				played[pitch] = true
+			}
+		}
+		pos.Frets = append(pos.Frets, frets)
+	}
+	return len(played) == len(pitches)
+}
+
+// ThreeNotesPerString returns one position starting on each degree of the
+// scale called name on the lowest string, seven for a seven note scale.
+// Each string, from the lowest sounding up, plays the next three notes of
+// the scale going up, so re-entrant strings take their turn by pitch.
+func ThreeNotesPerString(s *scale.Scale, name string, t tuning.Tuning) ([]Position, error) {
+	pitches, err := scalePitches(s, name)
+	if err != nil {
+		return nil, err
+	}
+	opens := openPitches(t)
+	order := make([]int, len(opens))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return opens[a] - opens[b] })
+	lowest := t.Strings[order[0]].PitchClass()
+
+	// Every scale note from the first fret of the lowest string up, as
+	// semitones above the open lowest string.
+	var run []int
+	for pitch := 1; pitch <= opens[order[len(order)-1]]+2*maxFret; pitch++ {
+		if pitches[(lowest+pitch)%12] {
+			run = append(run, pitch)
+		}
+	}
+
+	var positions []Position
+	for degree := 0; degree < len(pitches); degree++ {
+		pos := Position{Name: fmt.Sprintf("3 notes per string %d", degree+1), Frets: make([][]int, len(opens))}
+		next := rootIndex(run, s.Root().PitchClass()-lowest) + degree
+		for _, str := range order {
+			var frets []int
+			for n := 0; n < 3; n++ {
+				frets = append(frets, run[next]-opens[str])
+				next++
+			}
+			pos.Frets[str] = frets
+		}
+		pos.fitWindow()
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
+
+// rootIndex returns the index in run of the first root.
+func rootIndex(run []int, rootOffset int) int {
+	for i, pitch := range run {
+		if (pitch-rootOffset)%12 == 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// fitWindow sets the window to the frets the position plays, moving it
+// down an octave when it would go past the last fret and up an octave when
+// it would need open strings.
+func (pos *Position) fitWindow() {
+	low, high := maxFret, 0
+	for _, frets := range pos.Frets {
+		for _, fret := range frets {
+			low, high = min(low, fret), max(high, fret)
+		}
+	}
+	if high > maxFret && low-12 >= 0 {
+		pos.shift(-12)
+		low, high = low-12, high-12
+	}
+	if low < 1 && high+12 <= maxFret {
+		pos.shift(12)
+		low, high = low+12, high+12
+	}
+	pos.StartFret = max(low, 1)
+	pos.NumFrets = high - pos.StartFret + 1
+}
+
+// shift moves every fret of the position by semitones.
+func (pos *Position) shift(semitones int) {
+	for _, frets := range pos.Frets {
+		for i := range frets {
+			frets[i] += semitones
+		}
+	}
+}
+
+// openPitches returns each open string as semitones above the lowest
+// sounding one, in the octaves of the tuning.
+func openPitches(t tuning.Tuning) []int {
+	pitches := t.Pitches()
+	lowest := slices.Min(pitches)
+	for s := range pitches {
+		pitches[s] -= lowest
+	}
+	return pitches
+}
+
+// scalePitches returns the pitch classes in the scale called name.
+func scalePitches(s *scale.Scale, name string) (map[int]bool, error) {
+	intervals := s.ScaleInterval(name)
+	if len(intervals) == 0 {
+		return nil, fmt.Errorf("unknown scale %q", name)
+	}
+
+	inter := scale.NewInterval()
+	root := s.Root().PitchClass()
+	pitches := make(map[int]bool)
+	for _, i := range intervals {
+		offset, err := inter.IntervalToOffset(i)
+		if err != nil {
+			return nil, err
+		}
+		pitches[(root+offset)%12] = true
+	}
+	return pitches, nil
+}
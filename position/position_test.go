@@ -0,0 +1,61 @@
+package position
+
+import (
+	"testing"
+
+	"github.com/mrgrenier/GuitarScales/note"
+	"github.com/mrgrenier/GuitarScales/scale"
+	"github.com/mrgrenier/GuitarScales/tuning"
+)
+
+func TestCAGEDCoversEveryDegree(t *testing.T) {
+	for _, inst := range tuning.InstrumentNames() {
+		preset, _ := tuning.LookupInstrument(inst)
+		for _, root := range []string{"C", "F#", "A#"} {
+			s, err := scale.NewScale(note.Note{Name: root})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range s.ScaleNames() {
+				want, err := scalePitches(s, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				positions, err := CAGED(s, name, preset.Tuning)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, pos := range positions {
+					played := make(map[int]bool)
+					for str, frets := range pos.Frets {
+						for _, fret := range frets {
+							played[(preset.Tuning.Strings[str].PitchClass()+fret)%12] = true
+						}
+					}
+					if len(played) != len(want) {
+						t.Errorf("%s %s %s on %s: %s plays %d of %d notes", root, name, pos.Name, inst, pos.Name, len(played), len(want))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCAGEDRootOnLowestSoundingString(t *testing.T) {
+	ukulele, _ := tuning.LookupInstrument("ukulele")
+	s, err := scale.NewScale(note.Note{Name: "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions, err := CAGED(s, "ionian", ukulele.Tuning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// D is the second fret of the low C string, so the E shape starts on
+	// the first.
+	for _, pos := range positions {
+		if pos.Name == "E shape" && pos.StartFret != 1 {
+			t.Errorf("E shape starts on fret %d, want 1", pos.StartFret)
+		}
+	}
+}
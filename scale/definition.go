@@ -0,0 +1,65 @@
+package scale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Definition describes a scale or chord by the intervals of its notes above
+// the root, as written in a definitions file. Scales and chords can both be
+// asked for by their aliases; the category, tags, parent, mode and
+// description are for scales only.
+type Definition struct {
+	Name      string   `json:"name" yaml:"name"`
+	Intervals []string `json:"intervals" yaml:"intervals"`
+	Aliases   []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Category  string   `json:"category,omitempty" yaml:"category,omitempty"`
+	Tags      []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Parent and Mode name the scale this one is a mode of and which mode
+	// it is, counting the parent as mode 1.
+	Parent      string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Mode        int    `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Validate reports every problem with the definition: a missing or padded
+// name, no intervals, or an interval Interval does not know, joined with
+// errors.Join.
+func (d Definition) Validate() error {
+	var errs []error
+	if d.Name == "" {
+		errs = append(errs, fmt.Errorf("definition with no name"))
+	} else if strings.TrimSpace(d.Name) != d.Name {
+		errs = append(errs, fmt.Errorf("%q: name has leading or trailing spaces", d.Name))
+	}
+	if len(d.Intervals) == 0 {
+		errs = append(errs, fmt.Errorf("%q: no intervals", d.Name))
+	}
+	interval := NewInterval()
+	for _, intr := range d.Intervals {
+		if _, err := interval.IntervalToOffset(intr); err != nil {
+			errs = append(errs, fmt.Errorf("%q: %v", d.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// defined holds the scales added with Define, by name.
+var defined = make(map[string]Definition)
+
+// Define adds scales to the ones every new Scale knows, replacing any built-in
+// scale of the same name. Nothing is added unless every definition is valid.
+func Define(defs ...Definition) error {
+	var errs []error
+	for _, d := range defs {
+		errs = append(errs, d.Validate())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	for _, d := range defs {
+		defined[d.Name] = d
+	}
+	return nil
+}
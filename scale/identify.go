@@ -0,0 +1,67 @@
+package scale
+
+import (
+	"sort"
+
+	"github.com/mrgrenier/GuitarScales/note"
+)
+
+// Match is a scale, on one of the twelve roots, that holds a set of notes.
+type Match struct {
+	Root  note.Note
+	Scale string
+	// Extra counts the scale's notes that are not in the set, 0 for an
+	// exact match.
+	Extra int
+}
+
+// Identify returns every scale on every root that holds all of notes:
+// exact matches first, then the scales with the fewest extra notes. Roots
+// are spelled as their key signatures are, see note.Keys.
+func Identify(notes []note.Note) []Match {
+	pitches := make(map[int]bool)
+	for _, no := range notes {
+		pitches[no.PitchClass()] = true
+	}
+	if len(pitches) == 0 {
+		return nil
+	}
+
+	interval := NewInterval()
+	var matches []Match
+	for _, root := range note.Keys() {
+		s, err := NewScale(root)
+		if err != nil {
+			continue
+		}
+		for _, scaleName := range s.ScaleNames() {
+			scalePitches := make(map[int]bool)
+			for _, intr := range s.scales[scaleName] {
+				if offset, err := interval.IntervalToOffset(intr); err == nil {
+					scalePitches[(root.PitchClass()+offset)%12] = true
+				}
+			}
+
+			holds := true
+			for pitch := range pitches {
+				if !scalePitches[pitch] {
+					holds = false
+					break
+				}
+			}
+			if holds {
+				matches = append(matches, Match{Root: root, Scale: scaleName, Extra: len(scalePitches) - len(pitches)})
+			}
+		}
+	}
+
+	// Among equals, scales on the first note given come first.
+	first := notes[0].PitchClass()
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Extra != matches[j].Extra {
+			return matches[i].Extra < matches[j].Extra
+		}
+		return matches[i].Root.PitchClass() == first && matches[j].Root.PitchClass() != first
+	})
+	return matches
+}
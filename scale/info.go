@@ -0,0 +1,169 @@
+package scale
+
+import (
+	"sort"
+	"strings"
+)
+
+// Info describes a scale beyond its intervals.
+type Info struct {
+	// Aliases are other names the scale goes by, e.g. "natural minor" for
+	// aeolian.
+	Aliases []string
+	// Category is the family the scale belongs to, one of Categories.
+	Category string
+	// Parent is the scale this one is a mode of, and Mode which one,
+	// counting the parent itself as mode 1. Parent is "" when the scale is
+	// not known as a mode of another.
+	Parent      string
+	Mode        int
+	Description string
+	Tags        []string
+}
+
+// categories are the scale families in the order they are listed in.
+var categories = []string{"diatonic modes", "harmonic and melodic", "pentatonic", "blues", "bebop", "symmetric", "exotic"}
+
+// otherCategory holds the scales with no category.
+const otherCategory = "other"
+
+// builtinInfo describes the built-in scales.
+var builtinInfo = map[string]Info{
+	"ionian":     {Aliases: []string{"major"}, Category: "diatonic modes", Parent: "ionian", Mode: 1, Description: "The major scale."},
+	"dorian":     {Category: "diatonic modes", Parent: "ionian", Mode: 2, Description: "Minor with a major 6th."},
+	"phrygian":   {Category: "diatonic modes", Parent: "ionian", Mode: 3, Description: "Minor with a flat 2nd."},
+	"lydian":     {Category: "diatonic modes", Parent: "ionian", Mode: 4, Description: "Major with a sharp 4th."},
+	"mixolydian": {Aliases: []string{"dominant"}, Category: "diatonic modes", Parent: "ionian", Mode: 5, Description: "Major with a flat 7th."},
+	"aeolian":    {Aliases: []string{"natural minor", "minor"}, Category: "diatonic modes", Parent: "ionian", Mode: 6, Description: "The natural minor scale."},
+	"locrian":    {Category: "diatonic modes", Parent: "ionian", Mode: 7, Description: "Diminished, with a flat 2nd and 5th."},
+
+	"harmonic major":    {Category: "harmonic and melodic", Description: "Major with a flat 6th."},
+	"harmonic minor":    {Category: "harmonic and melodic", Parent: "harmonic minor", Mode: 1, Description: "Natural minor with a major 7th."},
+	"melodic minor":     {Aliases: []string{"jazz minor"}, Category: "harmonic and melodic", Parent: "melodic minor", Mode: 1, Description: "Major with a flat 3rd."},
+	"phrygian dominant": {Aliases: []string{"spanish phrygian", "freygish"}, Category: "harmonic and melodic", Parent: "harmonic minor", Mode: 5, Description: "Phrygian with a major 3rd."},
+	"mixolydian b6":     {Aliases: []string{"hindustan", "aeolian dominant"}, Category: "harmonic and melodic", Parent: "melodic minor", Mode: 5, Description: "Mixolydian with a flat 6th."},
+	"lydian dominant":   {Aliases: []string{"overtone", "acoustic"}, Category: "harmonic and melodic", Parent: "melodic minor", Mode: 4, Description: "Lydian with a flat 7th."},
+
+	"major pentatonic": {Category: "pentatonic", Parent: "major pentatonic", Mode: 1, Description: "The major scale without its 4th and 7th."},
+	"egyptian":         {Aliases: []string{"suspended pentatonic"}, Category: "pentatonic", Parent: "major pentatonic", Mode: 2, Description: "Pentatonic with no 3rd."},
+	"minor pentatonic": {Category: "pentatonic", Parent: "major pentatonic", Mode: 5, Description: "Natural minor without its 2nd and 6th."},
+
+	"minor blues":  {Aliases: []string{"blues"}, Category: "blues", Description: "Minor pentatonic with the flat 5th."},
+	"major blues":  {Category: "blues", Description: "Major pentatonic with the flat 3rd."},
+	"voodoo blues": {Category: "blues", Description: "Minor blues with a major 6th in place of the flat 7th."},
+
+	"bebop dominant": {Category: "bebop", Description: "Mixolydian with a passing major 7th."},
+	"bebop major":    {Category: "bebop", Description: "Major with a passing flat 6th."},
+
+	"chromatic":   {Category: "symmetric", Description: "All twelve notes."},
+	"symmetrical": {Aliases: []string{"half-whole diminished"}, Category: "symmetric", Description: "Alternating half and whole steps."},
+	"arabian":     {Aliases: []string{"whole-half diminished"}, Category: "symmetric", Description: "Alternating whole and half steps."},
+
+	"byzantine":            {Aliases: []string{"double harmonic major"}, Category: "exotic", Parent: "byzantine", Mode: 1, Description: "Major with a flat 2nd and flat 6th."},
+	"hungarian gypsy":      {Aliases: []string{"hungarian minor"}, Category: "exotic", Parent: "byzantine", Mode: 4, Description: "Harmonic minor with a sharp 4th."},
+	"chinese":              {Category: "exotic", Parent: "japanese hirajoshi", Mode: 5, Description: "Lydian without its 2nd and 6th."},
+	"composite":            {Category: "exotic", Description: "Eight notes mixing major and minor thirds."},
+	"enigmatic":            {Category: "exotic", Description: "Verdi's scale, with a flat 2nd and whole steps up to the 7th."},
+	"hungarian major":      {Category: "exotic", Description: "Lydian dominant with a sharp 2nd."},
+	"japanese":             {Aliases: []string{"in"}, Category: "exotic", Parent: "japanese hirajoshi", Mode: 4, Description: "Phrygian without its 3rd and 7th."},
+	"japanese hirajoshi":   {Aliases: []string{"hirajoshi"}, Category: "exotic", Parent: "japanese hirajoshi", Mode: 1, Description: "Natural minor without its 4th and 7th."},
+	"japanese iwato":       {Aliases: []string{"iwato"}, Category: "exotic", Parent: "japanese hirajoshi", Mode: 2, Description: "Locrian without its 3rd and 6th."},
+	"japanese kokin joshi": {Aliases: []string{"in sen"}, Category: "exotic", Parent: "japanese kumoi", Mode: 2, Description: "Phrygian without its 3rd and 6th."},
+	"japanese kumoi":       {Aliases: []string{"kumoi"}, Category: "exotic", Parent: "japanese kumoi", Mode: 1, Description: "Dorian without its 4th and 7th."},
+	"neapolitan major":     {Category: "exotic", Description: "Major with a flat 2nd and flat 3rd."},
+	"neapolitan minor":     {Category: "exotic", Description: "Harmonic minor with a flat 2nd."},
+	"oriental":             {Category: "exotic", Parent: "byzantine", Mode: 5, Description: "Mixolydian with a flat 2nd and flat 5th."},
+	"pelog":                {Aliases: []string{"balinese"}, Category: "exotic", Description: "Phrygian without its 4th and 7th, from the gamelan."},
+	"persian":              {Category: "exotic", Description: "Double harmonic major with a flat 5th."},
+	"dorian #4":            {Aliases: []string{"romanian", "ukrainian dorian"}, Category: "exotic", Parent: "harmonic minor", Mode: 4, Description: "Dorian with a sharp 4th."},
+	"scriabin":             {Category: "exotic", Description: "Five notes from Scriabin's mystic chord."},
+}
+
+// Info returns what is known about the scale called name. The fields of a
+// scale added with Define replace those of the built-in one they are set in.
+func (n *Scale) Info(name string) Info {
+	if info, ok := n.info[name]; ok {
+		return info
+	}
+	info := builtinInfo[name]
+	if d, ok := defined[name]; ok {
+		if len(d.Aliases) > 0 {
+			info.Aliases = d.Aliases
+		}
+		if d.Category != "" {
+			info.Category = d.Category
+		}
+		if d.Parent != "" {
+			info.Parent, info.Mode = d.Parent, d.Mode
+		}
+		if d.Description != "" {
+			info.Description = d.Description
+		}
+		if len(d.Tags) > 0 {
+			info.Tags = d.Tags
+		}
+	}
+	if info.Category == "" {
+		info.Category = otherCategory
+	}
+	return info
+}
+
+// Lookup returns the name of the scale called name or known by it as an
+// alias, ignoring case.
+func (n *Scale) Lookup(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, scaleName := range n.ScaleNames() {
+		if strings.ToLower(scaleName) == name {
+			return scaleName, true
+		}
+	}
+	for _, scaleName := range n.ScaleNames() {
+		for _, alias := range n.Info(scaleName).Aliases {
+			if strings.ToLower(alias) == name {
+				return scaleName, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Categories returns the categories that hold at least one scale, the
+// built-in ones first in their usual order, then any others alphabetically.
+func (n *Scale) Categories() []string {
+	used := make(map[string]bool)
+	for _, scaleName := range n.ScaleNames() {
+		used[n.Info(scaleName).Category] = true
+	}
+
+	var cats []string
+	for _, cat := range categories {
+		if used[cat] {
+			cats = append(cats, cat)
+			delete(used, cat)
+		}
+	}
+	var others []string
+	for cat := range used {
+		if cat != otherCategory {
+			others = append(others, cat)
+		}
+	}
+	sort.Strings(others)
+	cats = append(cats, others...)
+	if used[otherCategory] {
+		cats = append(cats, otherCategory)
+	}
+	return cats
+}
+
+// ScalesIn returns the sorted names of the scales in category.
+func (n *Scale) ScalesIn(category string) []string {
+	var names []string
+	for _, scaleName := range n.ScaleNames() {
+		if strings.EqualFold(n.Info(scaleName).Category, category) {
+			names = append(names, scaleName)
+		}
+	}
+	return names
+}
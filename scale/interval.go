@@ -1,6 +1,10 @@
 package scale
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type Interval struct {
 	offset map[string]int
@@ -31,13 +35,128 @@ func NewInterval() *Interval {
 	return i
 }
 
+// IntervalToOffset returns the semitones an interval is above the root
+// within the octave, 0 to 11, so the 9 lands where the 2 does.
 func (i *Interval) IntervalToOffset(interval string) (int, error) {
 	if inter, ok := i.offset[interval]; ok {
 		return inter, nil
 	}
-	return 0, fmt.Errorf("%s, not a valid interval", interval)
+	semitones, err := i.Semitones(interval)
+	if err != nil {
+		return 0, err
+	}
+	return semitones % 12, nil
 }
 
+// GetOffset returns the offsets of the simple intervals with at most one
+// sharp or flat, the names the diagrams label notes with.
 func (i *Interval) GetOffset() map[string]int {
 	return i.offset
 }
+
+// majorOffsets are the semitones above the root of the degrees of the major
+// scale, from the 1.
+var majorOffsets = []int{0, 2, 4, 5, 7, 9, 11}
+
+// maxDegree is the highest degree an interval can be written on, two
+// octaves above the root.
+const maxDegree = 15
+
+// Semitones returns the semitones an interval spans above the root,
+// counting whole octaves: 14 for "9", 21 for "13" and 9 for "bb7". An
+// interval is a degree from 1 to 15 raised by "#" or "##", or lowered by
+// "b" or "bb", from the major scale.
+func (i *Interval) Semitones(interval string) (int, error) {
+	degree, err := i.Degree(interval)
+	if err != nil {
+		return 0, err
+	}
+	accidental, number := splitInterval(interval)
+	if number != strconv.Itoa(degree) || degree > maxDegree {
+		return 0, fmt.Errorf("%s, not a valid interval", interval)
+	}
+
+	var shift int
+	switch accidental {
+	case "":
+	case "b":
+		shift = -1
+	case "bb":
+		shift = -2
+	case "#":
+		shift = 1
+	case "##":
+		shift = 2
+	default:
+		return 0, fmt.Errorf("%s, not a valid interval", interval)
+	}
+	semitones := 12*((degree-1)/7) + majorOffsets[(degree-1)%7] + shift
+	if semitones < 0 {
+		return 0, fmt.Errorf("%s, not a valid interval", interval)
+	}
+	return semitones, nil
+}
+
+// Degree returns the scale degree an interval is written on, e.g. 3 for
+// both "b3" and "3", or 9 for "b9", which decides the letter its note is
+// spelled with.
+func (i *Interval) Degree(interval string) (int, error) {
+	_, number := splitInterval(interval)
+	degree, err := strconv.Atoi(number)
+	if err != nil || degree < 1 {
+		return 0, fmt.Errorf("%s, not a valid interval", interval)
+	}
+	return degree, nil
+}
+
+// Name returns the interval written on degree that is semitones above the
+// root, in any octave: "b3" for 3 semitones on the 3rd, "bb7" for 9 on the
+// 7th, "#11" for 6 on the 11th. It fails when the degree would need more
+// than two sharps or flats.
+func (i *Interval) Name(degree, semitones int) (string, error) {
+	if degree < 1 || degree > maxDegree {
+		return "", fmt.Errorf("no degree %d", degree)
+	}
+	shift := ((semitones-majorOffsets[(degree-1)%7])%12 + 12) % 12
+	if shift > 6 {
+		shift -= 12
+	}
+	accidentals := map[int]string{-2: "bb", -1: "b", 0: "", 1: "#", 2: "##"}
+	accidental, ok := accidentals[shift]
+	if !ok {
+		return "", fmt.Errorf("%d semitones cannot be written on degree %d", semitones, degree)
+	}
+	return accidental + strconv.Itoa(degree), nil
+}
+
+// Simple returns an interval brought within the octave, keeping its
+// accidental: "2" for "9", "#4" for "#11" and "b6" for "b13".
+func (i *Interval) Simple(interval string) (string, error) {
+	degree, err := i.Degree(interval)
+	if err != nil {
+		return "", err
+	}
+	accidental, _ := splitInterval(interval)
+	return accidental + strconv.Itoa((degree-1)%7+1), nil
+}
+
+// Compound returns an interval an octave up when it is within the octave,
+// keeping its accidental: "9" for "2", "#11" for "#4" and "b13" for "b6".
+// Compound intervals are returned as they are.
+func (i *Interval) Compound(interval string) (string, error) {
+	degree, err := i.Degree(interval)
+	if err != nil {
+		return "", err
+	}
+	if degree > 7 {
+		return interval, nil
+	}
+	accidental, _ := splitInterval(interval)
+	return accidental + strconv.Itoa(degree+7), nil
+}
+
+// splitInterval splits an interval into its accidentals and its degree.
+func splitInterval(interval string) (accidental, number string) {
+	number = strings.TrimLeft(interval, "b#")
+	return interval[:len(interval)-len(number)], number
+}
@@ -0,0 +1,123 @@
+package scale
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Mode is a scale played from one of its notes other than the root.
+type Mode struct {
+	// Number counts the notes of the parent scale from 1 for its root up to
+	// the root of the mode.
+	Number    int
+	Name      string
+	Intervals []string
+	// Aliases are the built-in scales with the same notes, when the mode
+	// goes by another name.
+	Aliases []string
+}
+
+// modeNames are the usual names of the modes of scales whose modes are not
+// all built in, by mode number from 1.
+var modeNames = map[string][]string{
+	"harmonic minor": {"harmonic minor", "locrian #6", "ionian #5", "dorian #4", "phrygian dominant", "lydian #2", "ultralocrian"},
+	"melodic minor":  {"melodic minor", "dorian b2", "lydian augmented", "lydian dominant", "mixolydian b6", "locrian #2", "altered"},
+}
+
+// Modes returns every mode of the scale called name, starting with the
+// scale itself. The intervals of a mode are renamed from its own root,
+// keeping the degrees they are written on, so the 6 of ionian is the 1 of
+// aeolian and its 1 the b3. A mode goes by its usual name, then by the
+// name of the scale with its notes, and otherwise "<name> mode <number>";
+// the built-in scales with its notes are its aliases.
+func (n *Scale) Modes(name string) ([]Mode, error) {
+	intervals := n.scales[name]
+	if len(intervals) == 0 {
+		return nil, fmt.Errorf("unknown scale %q", name)
+	}
+	offsets := make([]int, len(intervals))
+	degrees := make([]int, len(intervals))
+	for i, intr := range intervals {
+		var err error
+		if offsets[i], err = n.interval.IntervalToOffset(intr); err != nil {
+			return nil, err
+		}
+		if degrees[i], err = n.interval.Degree(intr); err != nil {
+			return nil, err
+		}
+	}
+
+	known := make(map[int][]string)
+	for _, scaleName := range n.ScaleNames() {
+		m := n.mask(n.scales[scaleName])
+		known[m] = append(known[m], scaleName)
+	}
+
+	var modes []Mode
+	for k := range intervals {
+		mode := Mode{Number: k + 1}
+		for i := range intervals {
+			j := (k + i) % len(intervals)
+			offset := (offsets[j] - offsets[k] + 12) % 12
+			degree := ((degrees[j]-degrees[k])%7+7)%7 + 1
+			mode.Intervals = append(mode.Intervals, n.intervalName(degree, offset))
+		}
+
+		same := known[n.mask(mode.Intervals)]
+		switch names := modeNames[name]; {
+		case k < len(names):
+			mode.Name = names[k]
+		case len(same) > 0:
+			mode.Name = same[0]
+		default:
+			mode.Name = name + " mode " + strconv.Itoa(k+1)
+		}
+		for _, scaleName := range same {
+			if scaleName != mode.Name {
+				mode.Aliases = append(mode.Aliases, scaleName)
+			}
+		}
+		modes = append(modes, mode)
+	}
+	return modes, nil
+}
+
+// AddModes adds every mode of the scale called name to this Scale, with the
+// scale as their parent and the built-in scales with their notes as their
+// aliases, and returns their names from mode 1.
+func (n *Scale) AddModes(name string) ([]string, error) {
+	modes, err := n.Modes(name)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, mode := range modes {
+		if _, ok := n.scales[mode.Name]; !ok {
+			n.scales[mode.Name] = mode.Intervals
+			n.info[mode.Name] = Info{Aliases: mode.Aliases, Category: n.Info(name).Category, Parent: name, Mode: mode.Number}
+		}
+		names = append(names, mode.Name)
+	}
+	return names, nil
+}
+
+// intervalName writes the interval offset semitones above the root on
+// degree, or names it by its offset alone when degree cannot hold it.
+func (n *Scale) intervalName(degree, offset int) string {
+	name, err := n.interval.Name(degree, offset)
+	if err != nil {
+		return []string{"1", "b2", "2", "b3", "3", "4", "b5", "5", "b6", "6", "b7", "7"}[offset]
+	}
+	return name
+}
+
+// mask returns the pitch classes of intervals as a bit set.
+func (n *Scale) mask(intervals []string) int {
+	m := 0
+	for _, intr := range intervals {
+		if offset, err := n.interval.IntervalToOffset(intr); err == nil {
+			m |= 1 << offset
+		}
+	}
+	return m
+}
@@ -14,23 +14,29 @@ type Scale struct {
 	root     *ring.Ring
 	interval *Interval
 	scales   map[string][]string
+	// info describes the scales added to this Scale alone, such as modes.
+	info map[string]Info
 }
 
-func NewScale(root note.Note) *Scale {
+// NewScale returns the scales rooted on root, or an error when root is not one
+// of the twelve notes (see note.Valid).
+func NewScale(root note.Note) (*Scale, error) {
 
 	n := &Scale{}
 	n.interval = NewInterval()
 	n.scales = make(map[string][]string)
+	n.info = make(map[string]Info)
 	n.scales["ionian"] = append(n.scales["ionian"], "1", "2", "3", "4", "5", "6", "7")
 	n.scales["dorian"] = append(n.scales["dorian"], "1", "2", "b3", "4", "5", "6", "b7")
 	n.scales["phrygian"] = append(n.scales["phrygian"], "1", "b2", "b3", "4", "5", "b6", "b7")
 	n.scales["lydian"] = append(n.scales["lydian"], "1", "2", "3", "#4", "5", "6", "7")
 	n.scales["mixolydian"] = append(n.scales["mixolydian"], "1", "2", "3", "4", "5", "6", "b7")
-	n.scales["aoelian"] = append(n.scales["aoelian"], "1", "2", "b3", "4", "5", "b6", "b7")
+	n.scales["aeolian"] = append(n.scales["aeolian"], "1", "2", "b3", "4", "5", "b6", "b7")
 	n.scales["locrian"] = append(n.scales["locrian"], "1", "b2", "b3", "4", "b5", "b6", "b7")
 	n.scales["harmonic major"] = append(n.scales["harmonic major"], "1", "2", "3", "4", "5", "b6", "7")
 	n.scales["harmonic minor"] = append(n.scales["harmonic minor"], "1", "2", "b3", "4", "5", "b6", "7")
-	n.scales["bebop dominant"] = append(n.scales["bebop dominant"], "1", "2", "3", "4", "5", "#6", "7")
+	n.scales["melodic minor"] = append(n.scales["melodic minor"], "1", "2", "b3", "4", "5", "6", "7")
+	n.scales["bebop dominant"] = append(n.scales["bebop dominant"], "1", "2", "3", "4", "5", "6", "b7", "7")
 	n.scales["bebop major"] = append(n.scales["bebop major"], "1", "2", "3", "4", "5", "b6", "6", "7")
 	n.scales["minor blues"] = append(n.scales["minor blues"], "1", "b3", "4", "b5", "5", "b7")
 	n.scales["minor pentatonic"] = append(n.scales["minor pentatonic"], "1", "b3", "4", "5", "b7")
@@ -38,7 +44,6 @@ func NewScale(root note.Note) *Scale {
 	n.scales["major blues"] = append(n.scales["major blues"], "1", "2", "b3", "3", "5", "6")
 	n.scales["major pentatonic"] = append(n.scales["major pentatonic"], "1", "2", "3", "5", "6")
 	n.scales["arabian"] = append(n.scales["arabian"], "1", "2", "b3", "4", "b5", "b6", "6", "7")
-	n.scales["balinese"] = append(n.scales["balinese"], "1", "b2", "b3", "5", "b6")
 	n.scales["phrygian dominant"] = append(n.scales["phrygian dominant"], "1", "b2", "3", "4", "5", "b6", "b7")
 	n.scales["byzantine"] = append(n.scales["byzantine"], "1", "b2", "3", "4", "5", "b6", "7")
 	n.scales["chinese"] = append(n.scales["chinese"], "1", "3", "#4", "5", "7")
@@ -46,9 +51,9 @@ func NewScale(root note.Note) *Scale {
 	n.scales["composite"] = append(n.scales["composite"], "1", "b2", "b3", "3", "#4", "5", "b6", "b7")
 	n.scales["egyptian"] = append(n.scales["egyptian"], "1", "2", "4", "5", "b7")
 	n.scales["enigmatic"] = append(n.scales["enigmatic"], "1", "b2", "3", "#4", "#5", "#6", "7")
-	n.scales["hinduston"] = append(n.scales["hinduston"], "1", "2", "3", "4", "5", "b6", "b7")
+	n.scales["mixolydian b6"] = append(n.scales["mixolydian b6"], "1", "2", "3", "4", "5", "b6", "b7")
 	n.scales["hungarian major"] = append(n.scales["hungarian major"], "1", "#2", "3", "#4", "5", "6", "b7")
-	n.scales["hungarian gypsy "] = append(n.scales["hungarian gypsy "], "1", "2", "b3", "#4", "5", "b6", "7")
+	n.scales["hungarian gypsy"] = append(n.scales["hungarian gypsy"], "1", "2", "b3", "#4", "5", "b6", "7")
 	n.scales["japanese"] = append(n.scales["japanese"], "1", "b2", "4", "5", "b6")
 	n.scales["japanese hirajoshi"] = append(n.scales["japanese hirajoshi"], "1", "2", "b3", "5", "b6")
 	n.scales["japanese kumoi"] = append(n.scales["japanese kumoi"], "1", "2", "b3", "5", "6")
@@ -57,13 +62,18 @@ func NewScale(root note.Note) *Scale {
 	n.scales["neapolitan major"] = append(n.scales["neapolitan major"], "1", "b2", "b3", "4", "5", "6", "7")
 	n.scales["neapolitan minor"] = append(n.scales["neapolitan minor"], "1", "b2", "b3", "4", "5", "b6", "7")
 	n.scales["oriental"] = append(n.scales["oriental"], "1", "b2", "3", "4", "b5", "6", "b7")
-	n.scales["overtone"] = append(n.scales["overtone"], "1", "2", "3", "#4", "5", "6", "b7")
+	n.scales["lydian dominant"] = append(n.scales["lydian dominant"], "1", "2", "3", "#4", "5", "6", "b7")
 	n.scales["pelog"] = append(n.scales["pelog"], "1", "b2", "b3", "5", "b6")
 	n.scales["persian"] = append(n.scales["persian"], "1", "b2", "3", "4", "b5", "b6", "7")
-	n.scales["romanian"] = append(n.scales["romanian"], "1", "2", "b3", "#4", "5", "6", "b7")
-	n.scales["scribian"] = append(n.scales["scribian"], "1", "b2", "3", "5", "6")
+	n.scales["dorian #4"] = append(n.scales["dorian #4"], "1", "2", "b3", "#4", "5", "6", "b7")
+	n.scales["scriabin"] = append(n.scales["scriabin"], "1", "b2", "3", "5", "6")
 	n.scales["symmetrical"] = append(n.scales["symmetrical"], "1", "b2", "b3", "3", "#4", "5", "6", "b7")
 
+	// Definitions loaded with Define replace built-in ones of the same name.
+	for name, d := range defined {
+		n.scales[name] = append([]string(nil), d.Intervals...)
+	}
+
 	allnotes := []note.Note{
 		{Name: "A", Alternate: root.Alternate},
 		{Name: "A#", Alternate: root.Alternate},
@@ -86,19 +96,33 @@ func NewScale(root note.Note) *Scale {
 		n.notes = n.notes.Next()
 	}
 
-	n.SetRoot(root)
+	if err := n.SetRoot(root); err != nil {
+		return nil, err
+	}
 
-	return n
+	return n, nil
 }
 
-func (n *Scale) SetRoot(root note.Note) {
+// SetRoot moves the root to the note named as root is. The root keeps the
+// spelling it was given, such as Cb in place of B.
+func (n *Scale) SetRoot(root note.Note) error {
+	if !root.Valid() {
+		return fmt.Errorf("unknown root note %q", root.Name)
+	}
 	for i := 0; i < n.notes.Len(); i++ {
-		if n.notes.Value == root {
+		if n.notes.Value.(note.Note).Name == root.Name {
+			n.notes.Value = root
 			n.root = n.notes
 			break
 		}
 		n.notes = n.notes.Next()
 	}
+	return nil
+}
+
+// Root returns the root note of the scales.
+func (n *Scale) Root() note.Note {
+	return n.root.Value.(note.Note)
 }
 
 func (n *Scale) ScaleNames() []string {
@@ -152,6 +176,8 @@ func (n *Scale) ShowAll() {
 
 }
 
+// ShowNoteAt returns the note interval above the root, spelled with the
+// letter of the interval's degree: the b3 of A is C, the #2 is B#.
 func (n *Scale) ShowNoteAt(interval string) note.Note {
 
 	offset, err := n.interval.IntervalToOffset(interval)
@@ -159,10 +185,11 @@ func (n *Scale) ShowNoteAt(interval string) note.Note {
 		fmt.Println(err.Error())
 		return note.Note{}
 	}
+	degree, err := n.interval.Degree(interval)
+	if err != nil {
+		fmt.Println(err.Error())
+		return note.Note{}
+	}
 
-	n.notes = n.root
-	n.notes = n.notes.Move(offset)
-
-	no := n.notes.Value.(note.Note)
-	return no
+	return n.Root().Spell(degree, offset)
 }
@@ -0,0 +1,55 @@
+package tuning
+
+import "sort"
+
+// Instrument is a fretted instrument preset: its usual tuning and its scale
+// length, the distance from the nut to the bridge in inches.
+type Instrument struct {
+	Name        string
+	Tuning      Tuning
+	ScaleLength float64
+}
+
+// instruments holds each preset's tuning, written the way Parse reads it,
+// and its scale length.
+var instruments = map[string]struct {
+	tuning      string
+	scaleLength float64
+}{
+	"guitar":  {"standard", 25.5},
+	"guitar7": {"B1 E2 A2 D3 G3 B3 E4", 25.5},
+	"guitar8": {"F#1 B1 E2 A2 D3 G3 B3 E4", 27},
+	"bass":    {"E1 A1 D2 G2", 34},
+	"bass5":   {"B0 E1 A1 D2 G2", 35},
+	// The ukulele's G string is re-entrant, tuned above its C string.
+	"ukulele":  {"G4 C4 E4 A4", 13},
+	"mandolin": {"G3 D4 A4 E5", 13.875},
+	// Open G with the short fifth string, which sits on the bass side.
+	"banjo": {"G4 D3 G3 B3 D4", 26.25},
+}
+
+// InstrumentNames returns the sorted names of the instrument presets.
+func InstrumentNames() []string {
+	var names []string
+	for name := range instruments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupInstrument returns the instrument preset called name.
+func LookupInstrument(name string) (Instrument, bool) {
+	preset, ok := instruments[name]
+	if !ok {
+		return Instrument{}, false
+	}
+	t, err := Parse(preset.tuning)
+	if err != nil {
+		return Instrument{}, false
+	}
+	if t.Name == "" {
+		t.Name = name
+	}
+	return Instrument{Name: name, Tuning: t, ScaleLength: preset.scaleLength}, true
+}
@@ -0,0 +1,162 @@
+package tuning
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mrgrenier/GuitarScales/note"
+)
+
+// Tuning is the open strings of a fretted instrument, lowest string first.
+type Tuning struct {
+	Name    string
+	Strings []note.Note
+	// Octaves holds the octave of each string when the tuning gives them,
+	// as re-entrant tunings must: a ukulele's G string is above its C.
+	Octaves []int
+}
+
+// minStrings is the fewest strings a tuning can have. Diagrams space the
+// strings out between the lowest and the highest.
+const minStrings = 2
+
+// presets are the named tunings, written the way Parse reads them.
+var presets = map[string]string{
+	"standard":       "E A D G B E",
+	"drop d":         "D A D G B E",
+	"dadgad":         "D A D G A D",
+	"open g":         "D G D G B D",
+	"open d":         "D A D F# A D",
+	"half step down": "Eb Ab Db Gb Bb Eb",
+}
+
+// Standard returns EADGBE guitar tuning.
+func Standard() Tuning {
+	t, _ := Lookup("standard")
+	return t
+}
+
+// Names returns the sorted names of the preset tunings.
+func Names() []string {
+	var names []string
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the preset tuning called name. Case, dashes and
+// underscores are ignored, so "Drop-D" finds "drop d".
+func Lookup(name string) (Tuning, bool) {
+	key := strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(strings.TrimSpace(name)))
+	spec, ok := presets[key]
+	if !ok {
+		return Tuning{}, false
+	}
+	strs, octaves, err := parseStrings(spec)
+	if err != nil {
+		return Tuning{}, false
+	}
+	return Tuning{Name: key, Strings: strs, Octaves: octaves}, true
+}
+
+// Parse reads a preset name or the open strings from lowest to highest,
+// separated by spaces or commas, e.g. "D A D G B E". Either every string
+// or none has an octave, e.g. "G4 C4 E4 A4".
+func Parse(s string) (Tuning, error) {
+	if t, ok := Lookup(s); ok {
+		return t, nil
+	}
+	strs, octaves, err := parseStrings(s)
+	if err != nil {
+		return Tuning{}, err
+	}
+	t := Tuning{Strings: strs, Octaves: octaves}
+	if err := t.Validate(); err != nil {
+		return Tuning{}, err
+	}
+	return t, nil
+}
+
+// Validate checks that t has at least two strings.
+func (t Tuning) Validate() error {
+	if len(t.Strings) < minStrings {
+		return fmt.Errorf("tuning %q has %d strings, need at least %d", t.Notes(), len(t.Strings), minStrings)
+	}
+	return nil
+}
+
+func parseStrings(s string) ([]note.Note, []int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("empty tuning %q", s)
+	}
+
+	var strs []note.Note
+	var octaves []int
+	for _, field := range fields {
+		if !strings.ContainsAny(field, "0123456789") {
+			n, err := note.Parse(field)
+			if err != nil {
+				return nil, nil, fmt.Errorf("tuning %q: %w", s, err)
+			}
+			strs = append(strs, n)
+			continue
+		}
+		p, err := note.ParsePitch(field)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tuning %q: %w", s, err)
+		}
+		strs = append(strs, p.Note)
+		octaves = append(octaves, p.Octave)
+	}
+	if octaves != nil && len(octaves) != len(strs) {
+		return nil, nil, fmt.Errorf("tuning %q: give every string an octave or none", s)
+	}
+	return strs, octaves, nil
+}
+
+// Pitches returns the open strings as MIDI note numbers. Without octaves
+// the first string is taken to be in octave 2, as on a guitar, and each
+// string after it to be the first pitch above the one before.
+func (t Tuning) Pitches() []int {
+	var pitches []int
+	for s, n := range t.Strings {
+		switch {
+		case t.Octaves != nil:
+			pitches = append(pitches, note.Pitch{Note: n, Octave: t.Octaves[s]}.MIDI())
+		case s == 0:
+			pitches = append(pitches, note.Pitch{Note: n, Octave: 2}.MIDI())
+		default:
+			step := (n.PitchClass()-t.Strings[s-1].PitchClass()+11)%12 + 1
+			pitches = append(pitches, pitches[s-1]+step)
+		}
+	}
+	return pitches
+}
+
+// Notes returns the open strings spelled as they were given, lowest first.
+func (t Tuning) Notes() string {
+	var names []string
+	for s, n := range t.Strings {
+		if t.Octaves != nil {
+			names = append(names, note.Pitch{Note: n, Octave: t.Octaves[s]}.String())
+			continue
+		}
+		names = append(names, strings.TrimSpace(n.String()))
+	}
+	return strings.Join(names, " ")
+}
+
+// String returns the preset name and the notes, or only the notes for a
+// custom tuning.
+func (t Tuning) String() string {
+	if t.Name == "" {
+		return t.Notes()
+	}
+	return fmt.Sprintf("%s (%s)", t.Name, t.Notes())
+}
@@ -0,0 +1,71 @@
+package tuning
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mrgrenier/GuitarScales/note"
+)
+
+func TestInstrumentPitches(t *testing.T) {
+	tests := map[string]string{
+		"guitar":   "E2 A2 D3 G3 B3 E4",
+		"guitar7":  "B1 E2 A2 D3 G3 B3 E4",
+		"guitar8":  "F#1 B1 E2 A2 D3 G3 B3 E4",
+		"bass":     "E1 A1 D2 G2",
+		"bass5":    "B0 E1 A1 D2 G2",
+		"ukulele":  "G4 C4 E4 A4",
+		"mandolin": "G3 D4 A4 E5",
+		"banjo":    "G4 D3 G3 B3 D4",
+	}
+	for name, pitches := range tests {
+		inst, ok := LookupInstrument(name)
+		if !ok {
+			t.Fatalf("no instrument %q", name)
+		}
+		var want []int
+		for _, s := range strings.Fields(pitches) {
+			p, err := note.ParsePitch(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want = append(want, p.MIDI())
+		}
+		if got := inst.Tuning.Pitches(); !slices.Equal(got, want) {
+			t.Errorf("%s pitches = %v, want %v (%s)", name, got, want, pitches)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		pitches []int
+		wantErr bool
+	}{
+		{spec: "D A D G B E", pitches: []int{38, 45, 50, 55, 59, 64}},
+		{spec: "drop-d", pitches: []int{38, 45, 50, 55, 59, 64}},
+		{spec: "G4 C4 E4 A4", pitches: []int{67, 60, 64, 69}},
+		{spec: "G4 C E A", wantErr: true},
+		{spec: "E", wantErr: true},
+		{spec: "", wantErr: true},
+		{spec: "E H", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", tt.spec, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if !slices.Equal(got.Pitches(), tt.pitches) {
+			t.Errorf("Parse(%q).Pitches() = %v, want %v", tt.spec, got.Pitches(), tt.pitches)
+		}
+	}
+}